@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/iswangwenbin/gin-starter/internal/core"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// deadLetterCmd represents the dead-letters command
+var deadLetterCmd = &cobra.Command{
+	Use:   "dead-letters",
+	Short: "Inspect, replay or purge dead-lettered install events",
+	Long: `Inspect, replay or purge install events that were moved to the dead letter stream
+after failing to parse or exceeding the retry budget for ClickHouse writes.
+
+Replayed entries are written back to install_events_stream with their original fields.
+
+Examples:
+  gin-starter dead-letters list --size 50
+  gin-starter dead-letters replay 1718000000000-0 1718000000001-0
+  gin-starter dead-letters replay --all
+  gin-starter dead-letters purge --all --env production`,
+}
+
+var deadLetterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dead-lettered install events",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		size, _ := cmd.Flags().GetInt64("size")
+		after, _ := cmd.Flags().GetString("after")
+
+		resp, err := newDeadLetterService(cmd).List(context.Background(), &model.DeadLetterListRequest{After: after, Size: size})
+		if err != nil {
+			log.Fatalf("Failed to list dead letters: %v", err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(resp); err != nil {
+			log.Fatalf("Failed to print dead letters: %v", err)
+		}
+	},
+}
+
+var deadLetterReplayCmd = &cobra.Command{
+	Use:   "replay [id...]",
+	Short: "Replay dead-lettered install events back into the stream",
+	Run: func(cmd *cobra.Command, args []string) {
+		all := requireDeadLetterTargets(cmd, args)
+		deadLetterService := newDeadLetterService(cmd)
+
+		var replayed, skipped int64
+		var err error
+		if all {
+			replayed, skipped, err = deadLetterService.ReplayAll(context.Background())
+		} else {
+			replayed, err = deadLetterService.Replay(context.Background(), args)
+		}
+		if err != nil {
+			log.Fatalf("Failed to replay dead letters (%d replayed): %v", replayed, err)
+		}
+		fmt.Printf("Replayed %d dead letter(s)\n", replayed)
+		if skipped > 0 {
+			fmt.Printf("Skipped %d dead letter(s) without payload, purge them after inspection\n", skipped)
+		}
+	},
+}
+
+var deadLetterPurgeCmd = &cobra.Command{
+	Use:   "purge [id...]",
+	Short: "Delete dead-lettered install events",
+	Run: func(cmd *cobra.Command, args []string) {
+		all := requireDeadLetterTargets(cmd, args)
+		deadLetterService := newDeadLetterService(cmd)
+
+		var purged int64
+		var err error
+		if all {
+			purged, err = deadLetterService.PurgeAll(context.Background())
+		} else {
+			purged, err = deadLetterService.Purge(context.Background(), args)
+		}
+		if err != nil {
+			log.Fatalf("Failed to purge dead letters: %v", err)
+		}
+		fmt.Printf("Purged %d dead letter(s)\n", purged)
+	},
+}
+
+// newDeadLetterService 只启用 Redis 创建死信服务
+func newDeadLetterService(cmd *cobra.Command) *service.DeadLetterService {
+	env, _ := cmd.Root().PersistentFlags().GetString("env")
+	if GlobalConfig == nil {
+		log.Fatalf("Global config not loaded")
+	}
+
+	server, err := core.NewServer(env, core.StartCache)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	return service.NewDeadLetterService(server.Cache, server.Logger())
+}
+
+// requireDeadLetterTargets 要求指定 ID 或 --all，二者不能同时使用
+func requireDeadLetterTargets(cmd *cobra.Command, args []string) bool {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		log.Fatalf("Specify dead letter IDs or --all, but not both")
+	}
+	return all
+}
+
+func init() {
+	deadLetterListCmd.Flags().Int64("size", 20, "Number of entries to list (max 100)")
+	deadLetterListCmd.Flags().String("after", "", "List entries after this dead letter ID")
+	deadLetterReplayCmd.Flags().Bool("all", false, "Replay all dead letters")
+	deadLetterPurgeCmd.Flags().Bool("all", false, "Purge all dead letters")
+
+	deadLetterCmd.AddCommand(deadLetterListCmd, deadLetterReplayCmd, deadLetterPurgeCmd)
+	rootCmd.AddCommand(deadLetterCmd)
+}
@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/core"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Manage stored install events (retention, purge, archive, export)",
+	Long: `Manage install events stored in ClickHouse.
+
+Retention is configured under "retention": per-app policies are always applied as
+TTL rules, the default policy either as a TTL (mode ttl) or by dropping expired
+monthly partitions (mode partition, run by the worker every retention.interval).
+Partitions can be archived to local Parquet (zstd) or gzip-compressed Native files
+before they are dropped.
+
+Purge issues a lightweight DELETE for one app, limited to a device and/or to
+events before a point in time, for right-to-be-forgotten requests.
+
+Examples:
+  gin-starter events purge --app app_123 --device d41d8cd9 --dry-run
+  gin-starter events purge --app app_123 --before 2025-01-01
+  gin-starter events retention apply --env production
+  gin-starter events retention enforce
+  gin-starter events partitions
+  gin-starter events archive 202401 --format native --drop
+  gin-starter events drop 202401 --archive
+  gin-starter events rollups backfill
+  gin-starter events rollups backfill 202401 202402
+  gin-starter events export --app app_123 --start 2025-01-01 --end 2025-02-01 --format parquet --dir ./exports
+  gin-starter events export --app app_123 --format ndjson > events.ndjson`,
+}
+
+var eventsPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete install events of an app by device or time",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		appID, _ := cmd.Flags().GetString("app")
+		deviceID, _ := cmd.Flags().GetString("device")
+		before, _ := cmd.Flags().GetString("before")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		req := &model.PurgeEventsRequest{AppID: appID, DeviceID: deviceID}
+		if before != "" {
+			t, err := parseEventTime(before)
+			if err != nil {
+				log.Fatalf("Invalid --before: %v", err)
+			}
+			req.Before = &t
+		}
+
+		count, err := newRetentionService(cmd).Purge(context.Background(), req, dryRun)
+		if err != nil {
+			log.Fatalf("Failed to purge install events: %v", err)
+		}
+		if dryRun {
+			fmt.Printf("%d install event(s) would be deleted\n", count)
+			return
+		}
+		fmt.Printf("Deleted %d install event(s)\n", count)
+	},
+}
+
+var eventsRetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Apply install event retention policies",
+}
+
+var eventsRetentionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Replace the install_events TTL with the configured policies",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		policies, err := newRetentionService(cmd).ApplyTTL(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to apply retention TTL: %v", err)
+		}
+		if len(policies) == 0 {
+			fmt.Println("TTL removed, install events are kept forever")
+			return
+		}
+		for _, policy := range policies {
+			app := policy.AppID
+			if app == "" {
+				app = "(default)"
+			}
+			fmt.Printf("%s: %d day(s)\n", app, policy.Days)
+		}
+	},
+}
+
+var eventsRetentionEnforceCmd = &cobra.Command{
+	Use:   "enforce",
+	Short: "Archive and drop expired partitions (partition mode)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		retention := newRetentionService(cmd)
+		if retention.Config().Mode != model.RetentionModePartition {
+			log.Fatalf("retention.mode is %q, partitions are only dropped in %q mode", retention.Config().Mode, model.RetentionModePartition)
+		}
+
+		dropped, err := retention.EnforcePartitions(context.Background(), time.Now())
+		if len(dropped) > 0 {
+			fmt.Printf("Dropped partitions: %s\n", strings.Join(dropped, ", "))
+		}
+		if err != nil {
+			log.Fatalf("Failed to enforce retention: %v", err)
+		}
+		if len(dropped) == 0 {
+			fmt.Println("No expired partitions")
+		}
+	},
+}
+
+var eventsPartitionsCmd = &cobra.Command{
+	Use:   "partitions",
+	Short: "List install_events partitions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		partitions, err := newRetentionService(cmd).ListPartitions(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to list partitions: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PARTITION\tMIN DATE\tMAX DATE\tROWS\tBYTES")
+		for _, partition := range partitions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n", partition.ID,
+				partition.MinDate.Format(time.DateOnly), partition.MaxDate.Format(time.DateOnly), partition.Rows, partition.Bytes)
+		}
+		w.Flush()
+	},
+}
+
+var eventsArchiveCmd = &cobra.Command{
+	Use:   "archive <partition>",
+	Short: "Archive a partition (YYYYMM) to a local file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		drop, _ := cmd.Flags().GetBool("drop")
+		retention := newRetentionService(cmd)
+
+		if drop {
+			archiveAndDropPartition(cmd, retention, args[0])
+			return
+		}
+		path := archivePartition(cmd, retention, args[0])
+		fmt.Printf("Archived partition %s to %s\n", args[0], path)
+	},
+}
+
+var eventsDropCmd = &cobra.Command{
+	Use:   "drop <partition>",
+	Short: "Drop a partition (YYYYMM)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		archive, _ := cmd.Flags().GetBool("archive")
+		retention := newRetentionService(cmd)
+
+		if archive {
+			archiveAndDropPartition(cmd, retention, args[0])
+			return
+		}
+		if err := retention.DropPartition(context.Background(), args[0]); err != nil {
+			log.Fatalf("Failed to drop partition: %v", err)
+		}
+		fmt.Printf("Dropped partition %s\n", args[0])
+	},
+}
+
+var eventsRollupsCmd = &cobra.Command{
+	Use:   "rollups",
+	Short: "Maintain the hourly and daily install event rollups",
+}
+
+var eventsRollupsBackfillCmd = &cobra.Command{
+	Use:   "backfill [partition...]",
+	Short: "Rebuild rollups of partitions (YYYYMM) from deduplicated events",
+	Long: `Rebuild the hourly and daily rollups of the given partitions (YYYYMM) from the
+deduplicated events, using the same rule as the materialized views (one row per event,
+latest inserted_at wins). Without arguments, rebuilds the partitions holding events
+written before the rollup materialized views were created (ClickHouse migration 2).
+
+Each partition is aggregated into a staging table, events written meanwhile are added,
+and the rollup partition is then swapped in with REPLACE PARTITION; later events are
+added by the materialized views, so the command can be re-run safely. It also corrects
+rollups that counted an event written twice. Events written in the instant between the
+catch-up and the swap may be missed, so stop the worker while rebuilding a month that
+is still receiving events.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		retention := newRetentionService(cmd)
+		partitions := args
+		if len(partitions) == 0 {
+			var err error
+			if partitions, err = retention.RollupBackfillPartitions(context.Background()); err != nil {
+				log.Fatalf("Failed to list partitions to backfill: %v", err)
+			}
+		}
+		if len(partitions) == 0 {
+			fmt.Println("No partitions to backfill")
+			return
+		}
+
+		for _, id := range partitions {
+			if err := retention.RebuildRollups(context.Background(), id); err != nil {
+				log.Fatalf("Failed to rebuild rollups of partition %s: %v", id, err)
+			}
+			fmt.Printf("Rebuilt rollups of partition %s\n", id)
+		}
+	},
+}
+
+var eventsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export install events to CSV, NDJSON or Parquet",
+	Long: `Export install events ordered by event time. Results are encoded by ClickHouse and
+streamed to stdout, or to install_events_<start>_<end>.<ext> in --dir.
+
+The time range defaults to the last 7 days and may not exceed export.max_range.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		req := &model.InstallEventExportRequest{}
+		req.Format, _ = cmd.Flags().GetString("format")
+		req.AppID, _ = cmd.Flags().GetString("app")
+		req.DeviceID, _ = cmd.Flags().GetString("device")
+		req.ChannelID, _ = cmd.Flags().GetString("channel")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		if cmd.Flags().Changed("app-type") {
+			value, _ := cmd.Flags().GetUint8("app-type")
+			appType := model.AppType(value)
+			req.AppType = &appType
+		}
+		if cmd.Flags().Changed("install-type") {
+			value, _ := cmd.Flags().GetUint8("install-type")
+			installType := model.InstallType(value)
+			req.InstallType = &installType
+		}
+		if cmd.Flags().Changed("install-result") {
+			value, _ := cmd.Flags().GetUint8("install-result")
+			installResult := model.InstallResult(value)
+			req.InstallResult = &installResult
+		}
+		for flag, target := range map[string]**time.Time{"start": &req.StartTime, "end": &req.EndTime} {
+			value, _ := cmd.Flags().GetString(flag)
+			if value == "" {
+				continue
+			}
+			t, err := parseEventTime(value)
+			if err != nil {
+				log.Fatalf("Invalid --%s: %v", flag, err)
+			}
+			*target = &t
+		}
+
+		exportService := newExportService(cmd)
+		if dir == "" {
+			written, err := exportService.Export(context.Background(), req, os.Stdout)
+			if err != nil {
+				log.Fatalf("Failed to export install events: %v", err)
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d bytes\n", written)
+			return
+		}
+
+		path, written, err := exportService.ExportToDir(context.Background(), req, dir)
+		if err != nil {
+			log.Fatalf("Failed to export install events: %v", err)
+		}
+		fmt.Printf("Exported %d bytes to %s\n", written, path)
+	},
+}
+
+// newExportService 只启用 ClickHouse 创建导出服务
+func newExportService(cmd *cobra.Command) *service.InstallEventExportService {
+	env, _ := cmd.Root().PersistentFlags().GetString("env")
+	if GlobalConfig == nil {
+		log.Fatalf("Global config not loaded")
+	}
+
+	server, err := core.NewServer(env, core.StartClickHouse)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	repo := repository.NewRepositoryWithClickHouse(nil, server.ClickHouse)
+	return service.NewInstallEventExportService(service.NewBaseService(repo, nil, server.Logger()))
+}
+
+// newRetentionService 只启用 ClickHouse 创建保留策略服务
+func newRetentionService(cmd *cobra.Command) *service.RetentionService {
+	env, _ := cmd.Root().PersistentFlags().GetString("env")
+	if GlobalConfig == nil {
+		log.Fatalf("Global config not loaded")
+	}
+
+	server, err := core.NewServer(env, core.StartClickHouse)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	return service.NewRetentionService(repository.NewInstallEventRepository(server.ClickHouse), server.Logger())
+}
+
+// archivePartition 按 --dir、--format 归档分区，未指定时使用 retention 配置
+func archivePartition(cmd *cobra.Command, retention *service.RetentionService, id string) string {
+	dir, format := archiveOptions(cmd, retention)
+	path, err := retention.ArchivePartition(context.Background(), id, dir, format)
+	if err != nil {
+		log.Fatalf("Failed to archive partition: %v", err)
+	}
+	return path
+}
+
+// archiveAndDropPartition 归档并删除分区，归档期间分区有写入或变更时不删除
+func archiveAndDropPartition(cmd *cobra.Command, retention *service.RetentionService, id string) {
+	dir, format := archiveOptions(cmd, retention)
+	path, err := retention.ArchiveAndDropPartition(context.Background(), id, dir, format)
+	if path != "" {
+		fmt.Printf("Archived partition %s to %s\n", id, path)
+	}
+	if err != nil {
+		log.Fatalf("Failed to drop partition: %v", err)
+	}
+	fmt.Printf("Dropped partition %s\n", id)
+}
+
+// archiveOptions 归档目录和格式，--dir、--format 未指定时使用 retention 配置
+func archiveOptions(cmd *cobra.Command, retention *service.RetentionService) (string, string) {
+	dir, _ := cmd.Flags().GetString("dir")
+	format, _ := cmd.Flags().GetString("format")
+	if dir == "" {
+		dir = retention.Config().ArchiveDir
+	}
+	if format == "" {
+		format = retention.Config().ArchiveFormat
+	}
+	if dir == "" {
+		log.Fatalf("Specify --dir or set retention.archive_dir")
+	}
+	return dir, format
+}
+
+// parseEventTime 解析 RFC3339 时间或 UTC 日期（2006-01-02）
+func parseEventTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 time or YYYY-MM-DD date, got %q", value)
+	}
+	return t, nil
+}
+
+func init() {
+	eventsPurgeCmd.Flags().String("app", "", "App ID whose events are deleted (required)")
+	eventsPurgeCmd.Flags().String("device", "", "Only delete events of this device ID")
+	eventsPurgeCmd.Flags().String("before", "", "Only delete events before this time (RFC3339 or YYYY-MM-DD, UTC)")
+	eventsPurgeCmd.Flags().Bool("dry-run", false, "Only count the matching events")
+	eventsPurgeCmd.MarkFlagRequired("app")
+
+	for _, c := range []*cobra.Command{eventsArchiveCmd, eventsDropCmd} {
+		c.Flags().String("dir", "", "Archive directory (default retention.archive_dir)")
+		c.Flags().String("format", "", "Archive format: parquet or native (default retention.archive_format)")
+	}
+	eventsArchiveCmd.Flags().Bool("drop", false, "Drop the partition after archiving it")
+	eventsDropCmd.Flags().Bool("archive", false, "Archive the partition before dropping it")
+
+	eventsExportCmd.Flags().String("format", model.ExportFormatCSV, "Export format: csv, ndjson or parquet")
+	eventsExportCmd.Flags().String("dir", "", "Write the export to this directory instead of stdout")
+	eventsExportCmd.Flags().String("app", "", "Filter by app ID")
+	eventsExportCmd.Flags().Uint8("app-type", 0, "Filter by app type")
+	eventsExportCmd.Flags().String("device", "", "Filter by device ID")
+	eventsExportCmd.Flags().String("channel", "", "Filter by channel ID")
+	eventsExportCmd.Flags().Uint8("install-type", 0, "Filter by install type")
+	eventsExportCmd.Flags().Uint8("install-result", 0, "Filter by install result")
+	eventsExportCmd.Flags().String("start", "", "Start time, inclusive (RFC3339 or YYYY-MM-DD, UTC)")
+	eventsExportCmd.Flags().String("end", "", "End time, exclusive (RFC3339 or YYYY-MM-DD, UTC; default now)")
+
+	eventsRetentionCmd.AddCommand(eventsRetentionApplyCmd, eventsRetentionEnforceCmd)
+	eventsRollupsCmd.AddCommand(eventsRollupsBackfillCmd)
+	eventsCmd.AddCommand(eventsPurgeCmd, eventsRetentionCmd, eventsPartitionsCmd, eventsArchiveCmd, eventsDropCmd, eventsRollupsCmd, eventsExportCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
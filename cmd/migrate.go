@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/core"
+	"github.com/iswangwenbin/gin-starter/internal/migration"
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Run database schema migrations",
+	Long: `Run versioned schema migrations embedded in the binary.
+
+Subcommands without a database apply to MySQL; ClickHouse migrations live under
+"migrate clickhouse". Applied versions are recorded in the schema_migrations table
+of each database. MySQL migrations hold an advisory lock (GET_LOCK) so concurrent
+deploys wait for each other instead of racing.
+
+A migration that fails halfway is marked dirty; fix the schema by hand, then run
+force with the last version that is fully applied.
+
+Examples:
+  gin-starter migrate status
+  gin-starter migrate up --env production
+  gin-starter migrate down --steps 1
+  gin-starter migrate create add_user_locale
+  gin-starter migrate force 3
+  gin-starter migrate clickhouse up`,
+}
+
+var migrateClickHouseCmd = &cobra.Command{
+	Use:   "clickhouse",
+	Short: "Run ClickHouse migrations (install_events)",
+}
+
+// migrationTarget 一个数据库的迁移命令配置
+type migrationTarget struct {
+	dir         string // create 写入迁移文件的源码目录
+	newMigrator func(cmd *cobra.Command) *migration.Migrator
+}
+
+// migrateCommands 为一个数据库创建 up、down、status、create、force 子命令
+func migrateCommands(target migrationTarget) []*cobra.Command {
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			steps, _ := cmd.Flags().GetInt("steps")
+			applied, err := target.newMigrator(cmd).Up(context.Background(), steps)
+			printMigrations("Applied", applied)
+			if err != nil {
+				log.Fatalf("Failed to apply migrations: %v", err)
+			}
+		},
+	}
+	up.Flags().Int("steps", 0, "Number of migrations to apply (0 applies all)")
+
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back applied migrations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			steps, _ := cmd.Flags().GetInt("steps")
+			reverted, err := target.newMigrator(cmd).Down(context.Background(), steps)
+			printMigrations("Reverted", reverted)
+			if err != nil {
+				log.Fatalf("Failed to roll back migrations: %v", err)
+			}
+		},
+	}
+	down.Flags().Int("steps", 1, "Number of migrations to roll back")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show migration status",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			statuses, err := target.newMigrator(cmd).Status(context.Background())
+			if err != nil {
+				log.Fatalf("Failed to read migration status: %v", err)
+			}
+			printMigrationStatus(statuses)
+		},
+	}
+
+	create := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create empty up/down migration files with the next version",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir, _ := cmd.Flags().GetString("dir")
+			files, err := migration.Create(dir, args[0])
+			if err != nil {
+				log.Fatalf("Failed to create migration: %v", err)
+			}
+			for _, file := range files {
+				fmt.Printf("Created %s\n", file)
+			}
+		},
+	}
+	create.Flags().String("dir", target.dir, "Directory of the embedded migration files")
+
+	force := &cobra.Command{
+		Use:   "force <version>",
+		Short: "Mark migrations up to a version as applied without running them",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			version, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				log.Fatalf("Invalid version %q: %v", args[0], err)
+			}
+			if err := target.newMigrator(cmd).Force(context.Background(), version); err != nil {
+				log.Fatalf("Failed to force migration version: %v", err)
+			}
+			fmt.Printf("Forced version %d\n", version)
+		},
+	}
+
+	return []*cobra.Command{up, down, status, create, force}
+}
+
+// newMySQLMigrator 只启用数据库创建迁移器
+func newMySQLMigrator(cmd *cobra.Command) *migration.Migrator {
+	server := newMigrationServer(cmd, core.StartDatabase)
+	migrator, err := migration.NewMySQLMigrator(server.DB, server.Logger())
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator
+}
+
+// newClickHouseMigrator 只启用 ClickHouse 创建迁移器
+func newClickHouseMigrator(cmd *cobra.Command) *migration.Migrator {
+	server := newMigrationServer(cmd, core.StartClickHouse)
+	migrator, err := migration.NewClickHouseMigrator(server.ClickHouse, server.Logger())
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator
+}
+
+func newMigrationServer(cmd *cobra.Command, option core.Option) *core.Server {
+	env, _ := cmd.Root().PersistentFlags().GetString("env")
+	if GlobalConfig == nil {
+		log.Fatalf("Global config not loaded")
+	}
+
+	server, err := core.NewServer(env, option)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+// checkMigrations 检查 MySQL 迁移是否已全部执行
+func checkMigrations(server *core.Server) error {
+	migrator, err := migration.NewMySQLMigrator(server.DB, server.Logger())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d pending mysql migration(s), run \"gin-starter migrate up\" first", pending)
+	}
+	return nil
+}
+
+func printMigrations(action string, migrations []migration.Migration) {
+	for _, m := range migrations {
+		fmt.Printf("%s %d_%s\n", action, m.Version, m.Name)
+	}
+	if len(migrations) == 0 {
+		fmt.Println("No migrations to run")
+	}
+}
+
+func printMigrationStatus(statuses []migration.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state := "pending"
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Missing:
+			state = "missing file"
+		case s.Modified:
+			state = "applied (modified)"
+		case s.Applied:
+			state = "applied"
+		}
+		appliedAt := ""
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	w.Flush()
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateCommands(migrationTarget{
+		dir:         migration.MySQLDir,
+		newMigrator: newMySQLMigrator,
+	})...)
+	migrateClickHouseCmd.AddCommand(migrateCommands(migrationTarget{
+		dir:         migration.ClickHouseDir,
+		newMigrator: newClickHouseMigrator,
+	})...)
+
+	migrateCmd.AddCommand(migrateClickHouseCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/iswangwenbin/gin-starter/internal/core"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// rolesCmd represents the roles command
+var rolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "List roles and assign them to users",
+	Long: `List roles and assign or remove user roles directly in the database.
+
+Assigning roles over HTTP requires the roles:assign permission, so the first
+administrator of a new deployment is created here. Users are given by ID or
+username. Access tokens issued before the change are revoked; the user logs in
+again (or refreshes) to get a token with the new roles.
+
+Examples:
+  gin-starter roles list
+  gin-starter roles assign admin_user admin --env production
+  gin-starter roles remove 42 admin`,
+}
+
+var rolesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List roles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		roles, err := newRoleCommand(cmd).rbac.ListRoles(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to list roles: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDESCRIPTION")
+		for _, role := range roles {
+			fmt.Fprintf(w, "%s\t%s\n", role.Name, role.Description)
+		}
+		w.Flush()
+	},
+}
+
+var rolesAssignCmd = &cobra.Command{
+	Use:   "assign <user> <role>",
+	Short: "Assign a role to a user",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		rc := newRoleCommand(cmd)
+		user := rc.user(args[0])
+		if err := rc.rbac.AssignRole(context.Background(), user.ID, args[1]); err != nil {
+			log.Fatalf("Failed to assign role: %v", err)
+		}
+		rc.expireTokens(user.ID)
+		fmt.Printf("Assigned role %s to user %s (id %d)\n", args[1], user.Username, user.ID)
+	},
+}
+
+var rolesRemoveCmd = &cobra.Command{
+	Use:   "remove <user> <role>",
+	Short: "Remove a role from a user",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		rc := newRoleCommand(cmd)
+		user := rc.user(args[0])
+		if err := rc.rbac.RemoveRole(context.Background(), user.ID, args[1]); err != nil {
+			log.Fatalf("Failed to remove role: %v", err)
+		}
+		rc.expireTokens(user.ID)
+		fmt.Printf("Removed role %s from user %s (id %d)\n", args[1], user.Username, user.ID)
+	},
+}
+
+// roleCommand 角色命令使用的依赖
+type roleCommand struct {
+	server   *core.Server
+	repo     *repository.RepositoryManager
+	rbac     *service.RBACService
+	denylist *middleware.TokenDenylist
+}
+
+// newRoleCommand 启用数据库和 Redis，Redis 用于使旧访问令牌失效
+func newRoleCommand(cmd *cobra.Command) *roleCommand {
+	env, _ := cmd.Root().PersistentFlags().GetString("env")
+	if GlobalConfig == nil {
+		log.Fatalf("Global config not loaded")
+	}
+
+	server, err := core.NewServer(env, core.StartDatabase, core.StartCache)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	repo := repository.NewRepository(server.DB)
+	return &roleCommand{
+		server:   server,
+		repo:     repo,
+		rbac:     service.NewRBACService(service.NewBaseService(repo, server.Cache, server.Logger())),
+		denylist: middleware.NewTokenDenylist(server.Cache),
+	}
+}
+
+// user 按 ID 或用户名查找用户
+func (rc *roleCommand) user(identifier string) *model.User {
+	ctx := context.Background()
+	users := rc.repo.UserRepository()
+
+	var user *model.User
+	var err error
+	if id, parseErr := strconv.ParseUint(identifier, 10, 64); parseErr == nil {
+		user, err = users.GetByID(ctx, uint(id))
+	} else {
+		user, err = users.GetByUsername(ctx, identifier)
+	}
+	if err != nil {
+		log.Fatalf("Failed to find user %q: %v", identifier, err)
+	}
+	return user
+}
+
+// expireTokens 与 HTTP 接口一致，角色变更后使旧访问令牌失效
+func (rc *roleCommand) expireTokens(userID uint64) {
+	if err := rc.denylist.RevokeUser(context.Background(), userID); err != nil {
+		rc.server.Logger().Warn("Failed to expire user tokens", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+}
+
+func init() {
+	rolesCmd.AddCommand(rolesListCmd, rolesAssignCmd, rolesRemoveCmd)
+	rootCmd.AddCommand(rolesCmd)
+}
@@ -43,6 +43,13 @@ Examples:
 			log.Fatalf("Failed to create server: %v", err)
 		}
 
+		// 数据库结构落后于当前版本时拒绝启动
+		if cfg.Database.RequireMigrations {
+			if err := checkMigrations(server); err != nil {
+				log.Fatalf("Database schema check failed: %v", err)
+			}
+		}
+
 		// 创建生命周期管理器并运行
 		lifecycle := core.NewLifecycle(server)
 		fmt.Printf("Starting server in %s mode on %s...\n", env, cfg.GetServerAddress())
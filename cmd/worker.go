@@ -4,15 +4,18 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/iswangwenbin/gin-starter/internal/core"
 	"github.com/iswangwenbin/gin-starter/internal/worker"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 )
 
 // workerCmd represents the worker command
@@ -23,6 +26,7 @@ var workerCmd = &cobra.Command{
 
 The worker consumes install events from Redis Stream and writes them to ClickHouse.
 It runs independently from the main server process.
+When worker.http_addr is set, /healthz, /readyz, /status and /metrics are served on that address.
 
 Examples:
   gin-starter worker                   # Start with default settings
@@ -71,6 +75,19 @@ Examples:
 		}
 		defer installEventWorker.Stop()
 
+		// 启动健康检查、状态和指标服务
+		if cfg.Worker.HTTPAddr != "" {
+			statusServer := worker.NewStatusServer(cfg.Worker.HTTPAddr, installEventWorker, server.Logger())
+			statusServer.Start()
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := statusServer.Shutdown(ctx); err != nil {
+					server.Logger().Error("Worker status server shutdown error", zap.Error(err))
+				}
+			}()
+		}
+
 		// 等待停止信号
 		server.Logger().Info("Install event worker started, waiting for signals...")
 		quit := make(chan os.Signal, 1)
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"go.uber.org/zap"
+)
+
+type AppController struct {
+	*BaseController
+	appService *service.AppService
+}
+
+func NewAppController(base *BaseController) *AppController {
+	repo := repository.NewRepository(base.DB)
+	baseService := service.NewBaseService(repo, base.Cache, base.Logger)
+	return &AppController{
+		BaseController: base,
+		appService:     service.NewAppService(baseService),
+	}
+}
+
+func (ac *AppController) List(c *gin.Context) {
+	apps, err := ac.appService.ListApps()
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, apps)
+}
+
+func (ac *AppController) Create(c *gin.Context) {
+	var req model.CreateAppRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if req.AppID == "" || req.Name == "" {
+		BadRequest(c, "app_id and name are required")
+		return
+	}
+
+	app, err := ac.appService.CreateApp(&req)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, app)
+}
+
+func (ac *AppController) Get(c *gin.Context) {
+	app, err := ac.appService.GetApp(c.Param("app_id"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, app)
+}
+
+func (ac *AppController) UpdateStatus(c *gin.Context) {
+	var req model.UpdateAppStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if req.Status == nil || (*req.Status != model.AppStatusActive && *req.Status != model.AppStatusDisabled) {
+		BadRequest(c, "status must be 0 or 1")
+		return
+	}
+
+	appID := c.Param("app_id")
+	if err := ac.appService.UpdateStatus(appID, *req.Status); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	ac.GetLogger(c).Info("app status updated", zap.String("app_id", appID), zap.Int("status", *req.Status))
+	Success(c, nil)
+}
+
+// CreateKey 新增密钥，Secret 只在响应中返回一次
+func (ac *AppController) CreateKey(c *gin.Context) {
+	key, err := ac.appService.CreateKey(c.Param("app_id"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, key)
+}
+
+// RotateKey 轮换密钥，旧密钥在宽限期内仍可使用
+func (ac *AppController) RotateKey(c *gin.Context) {
+	key, err := ac.appService.RotateKey(c.Param("app_id"), c.Param("key_id"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, key)
+}
+
+func (ac *AppController) RevokeKey(c *gin.Context) {
+	if err := ac.appService.RevokeKey(c.Param("app_id"), c.Param("key_id")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -8,16 +9,18 @@ import (
 )
 
 type BaseController struct {
-	DB     *gorm.DB
-	Cache  *redis.Client
-	Logger *zap.Logger
+	DB         *gorm.DB
+	Cache      *redis.Client
+	ClickHouse clickhouse.Conn
+	Logger     *zap.Logger
 }
 
-func NewBaseController(db *gorm.DB, cache *redis.Client, logger *zap.Logger) *BaseController {
+func NewBaseController(db *gorm.DB, cache *redis.Client, ch clickhouse.Conn, logger *zap.Logger) *BaseController {
 	return &BaseController{
-		DB:     db,
-		Cache:  cache,
-		Logger: logger,
+		DB:         db,
+		Cache:      cache,
+		ClickHouse: ch,
+		Logger:     logger,
 	}
 }
 
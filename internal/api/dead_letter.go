@@ -0,0 +1,94 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+)
+
+type DeadLetterController struct {
+	*BaseController
+	deadLetterService *service.DeadLetterService
+}
+
+func NewDeadLetterController(base *BaseController) *DeadLetterController {
+	return &DeadLetterController{
+		BaseController:    base,
+		deadLetterService: service.NewDeadLetterService(base.Cache, base.Logger),
+	}
+}
+
+// List 分页查看死信
+func (dc *DeadLetterController) List(c *gin.Context) {
+	var req model.DeadLetterListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := dc.deadLetterService.List(c.Request.Context(), &req)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, resp)
+}
+
+// Replay 将死信重新写入安装事件流
+func (dc *DeadLetterController) Replay(c *gin.Context) {
+	req, ok := dc.bindAction(c)
+	if !ok {
+		return
+	}
+
+	var affected, skipped int64
+	var err error
+	if req.All {
+		affected, skipped, err = dc.deadLetterService.ReplayAll(c.Request.Context())
+	} else {
+		affected, err = dc.deadLetterService.Replay(c.Request.Context(), req.IDs)
+	}
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	SuccessWithMessage(c, "Dead letters replayed", model.DeadLetterActionResponse{Affected: affected, Skipped: skipped})
+}
+
+// Purge 删除死信
+func (dc *DeadLetterController) Purge(c *gin.Context) {
+	req, ok := dc.bindAction(c)
+	if !ok {
+		return
+	}
+
+	var affected int64
+	var err error
+	if req.All {
+		affected, err = dc.deadLetterService.PurgeAll(c.Request.Context())
+	} else {
+		affected, err = dc.deadLetterService.Purge(c.Request.Context(), req.IDs)
+	}
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	SuccessWithMessage(c, "Dead letters purged", model.DeadLetterActionResponse{Affected: affected})
+}
+
+// bindAction 要求指定 ids 或 all，避免空请求误操作
+func (dc *DeadLetterController) bindAction(c *gin.Context) (*model.DeadLetterActionRequest, bool) {
+	var req model.DeadLetterActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return nil, false
+	}
+	if !req.All && len(req.IDs) == 0 {
+		BadRequest(c, "ids is required unless all is true")
+		return nil, false
+	}
+	return &req, true
+}
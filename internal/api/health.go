@@ -44,13 +44,26 @@ func (h *HealthController) Check(c *gin.Context) {
 		}
 	}
 	
-	// 判断整体状态
+	// 检查ClickHouse连接
+	if h.ClickHouse != nil {
+		if h.ClickHouse.Ping(c.Request.Context()) != nil {
+			services["clickhouse"] = "down"
+		} else {
+			services["clickhouse"] = "up"
+		}
+	}
+	
+	// 判断整体状态，ClickHouse 只影响安装事件查询，不可用时为 degraded
 	status := "healthy"
-	for _, serviceStatus := range services {
-		if serviceStatus == "down" {
+	for name, serviceStatus := range services {
+		if serviceStatus != "down" {
+			continue
+		}
+		if name != "clickhouse" {
 			status = "unhealthy"
 			break
 		}
+		status = "degraded"
 	}
 	
 	response := HealthResponse{
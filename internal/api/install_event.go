@@ -0,0 +1,391 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxBatchEvents 单次批量上报的事件数上限
+	maxBatchEvents = 1000
+	// maxDecodedBatchSize 解压后请求体大小上限，防止压缩炸弹
+	maxDecodedBatchSize = 64 << 20
+	// maxNDJSONLineSize NDJSON 单行大小上限
+	maxNDJSONLineSize = 1 << 20
+)
+
+// contentTypeNDJSON 换行分隔的 JSON 请求体
+const contentTypeNDJSON = "application/x-ndjson"
+
+// eventValidator 按 model 中的 validate 标签校验事件，字段名使用 JSON 名称
+var eventValidator = newEventValidator()
+
+type InstallEventController struct {
+	*BaseController
+	installEventService *service.InstallEventService
+	queryService        *service.InstallEventQueryService
+	exportService       *service.InstallEventExportService
+	appService          *service.AppService
+}
+
+func NewInstallEventController(base *BaseController) *InstallEventController {
+	repo := repository.NewRepositoryWithClickHouse(base.DB, base.ClickHouse)
+	baseService := service.NewBaseService(repo, base.Cache, base.Logger)
+	return &InstallEventController{
+		BaseController:      base,
+		installEventService: service.NewInstallEventService(base.Cache, base.Logger),
+		queryService:        service.NewInstallEventQueryService(baseService),
+		exportService:       service.NewInstallEventExportService(baseService),
+		appService:          service.NewAppService(baseService),
+	}
+}
+
+// List 按时间倒序游标分页查询事件
+func (ic *InstallEventController) List(c *gin.Context) {
+	var req model.InstallEventListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := ic.queryService.List(c.Request.Context(), &req)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, resp)
+}
+
+// Create 上报单个安装事件
+func (ic *InstallEventController) Create(c *gin.Context) {
+	var req model.CreateInstallEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	if err := eventValidator.Struct(&req); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if err := ic.checkAppIDs(c, []*model.CreateInstallEventRequest{&req}); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if err := ic.installEventService.Create(c.Request.Context(), &req); err != nil {
+		ic.GetLogger(c).Error("Failed to create install event",
+			zap.String("event_id", req.EventID),
+			zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	SuccessWithMessage(c, "Install event accepted", gin.H{"event_id": req.EventID})
+}
+
+// CreateBatch 批量上报安装事件，支持 JSON {"events": [...]} 或 NDJSON，NDJSON 可使用 gzip 压缩
+func (ic *InstallEventController) CreateBatch(c *gin.Context) {
+	events, err := decodeEventBatch(c)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if len(events) == 0 {
+		BadRequest(c, "No events provided")
+		return
+	}
+	if len(events) > maxBatchEvents {
+		BadRequest(c, fmt.Sprintf("Too many events in batch, maximum is %d", maxBatchEvents))
+		return
+	}
+
+	if err := validateEventBatch(events); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if err := ic.checkAppIDs(c, events); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	result, err := ic.installEventService.CreateBatch(c.Request.Context(), events)
+	if err != nil {
+		ic.GetLogger(c).Error("Failed to create install events batch",
+			zap.Int("count", len(events)),
+			zap.Error(err))
+		HandleError(c, err)
+		return
+	}
+
+	SuccessWithMessage(c, "Install events accepted", result)
+}
+
+// Stats 统计时间窗口内的安装数据
+func (ic *InstallEventController) Stats(c *gin.Context) {
+	var req model.InstallStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	stats, err := ic.queryService.Stats(c.Request.Context(), &req)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, stats)
+}
+
+// TimeSeries 按粒度、分组维度和时区查询安装趋势
+func (ic *InstallEventController) TimeSeries(c *gin.Context) {
+	var req model.InstallTimeSeriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	series, err := ic.queryService.TimeSeries(c.Request.Context(), &req)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, series)
+}
+
+// CreateExport 创建异步导出任务，过滤条件与事件查询相同
+func (ic *InstallEventController) CreateExport(c *gin.Context) {
+	userID, ok := exportUserID(c)
+	if !ok {
+		return
+	}
+
+	var req model.InstallEventExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	job, err := ic.exportService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	SuccessWithMessage(c, "Export accepted", job)
+}
+
+// GetExport 查询导出任务状态，完成后返回下载地址
+func (ic *InstallEventController) GetExport(c *gin.Context) {
+	userID, ok := exportUserID(c)
+	if !ok {
+		return
+	}
+
+	job, err := ic.exportService.Get(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if job.Status == model.ExportStatusCompleted {
+		job.DownloadURL = c.Request.URL.Path + "/download"
+	}
+
+	Success(c, job)
+}
+
+// DownloadExport 下载已完成的导出文件
+func (ic *InstallEventController) DownloadExport(c *gin.Context) {
+	userID, ok := exportUserID(c)
+	if !ok {
+		return
+	}
+
+	job, path, err := ic.exportService.File(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, job.FileName)
+}
+
+// RecoverExports 启动时将上次运行遗留的未完成导出任务标记为失败
+func (ic *InstallEventController) RecoverExports(ctx context.Context) {
+	if _, err := ic.exportService.FailOrphaned(ctx); err != nil {
+		ic.Logger.Warn("Failed to recover install event exports", zap.Error(err))
+	}
+}
+
+// ShutdownExports 等待执行中的导出任务完成，超时后取消
+func (ic *InstallEventController) ShutdownExports(ctx context.Context) error {
+	return ic.exportService.Shutdown(ctx)
+}
+
+// exportUserID 导出任务归属于创建它的用户
+func exportUserID(c *gin.Context) (uint64, bool) {
+	userID, ok := c.Get("user_id")
+	id, isUint := userID.(uint64)
+	if !ok || !isUint {
+		HandleError(c, ErrAccessDenied)
+		return 0, false
+	}
+	return id, true
+}
+
+// checkAppIDs 签名认证通过时事件必须属于认证的应用；认证关闭时要求 app_id 已注册且处于启用状态
+func (ic *InstallEventController) checkAppIDs(c *gin.Context, events []*model.CreateInstallEventRequest) error {
+	authenticated := c.GetString(middleware.AppIDKey)
+	checked := make(map[string]bool)
+
+	for _, event := range events {
+		if checked[event.AppID] {
+			continue
+		}
+		checked[event.AppID] = true
+
+		if authenticated != "" {
+			if event.AppID != authenticated {
+				return errorsx.New(errorsx.CodeForbidden, fmt.Sprintf("Event app_id %q does not match authenticated app", event.AppID))
+			}
+			continue
+		}
+
+		if cfg := configx.GetConfig(); cfg != nil && !cfg.AppAuth.Enabled {
+			if err := ic.appService.ValidateApp(c.Request.Context(), event.AppID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		return errorsx.New(errorsx.CodeUnauthorized, "App authentication required")
+	}
+	return nil
+}
+
+// decodeEventBatch 按 Content-Type 和 Content-Encoding 解析批量请求体
+func decodeEventBatch(c *gin.Context) ([]*model.CreateInstallEventRequest, error) {
+	var body io.Reader = c.Request.Body
+
+	if strings.EqualFold(c.GetHeader("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, errorsx.New(errorsx.CodeBadRequest, "Invalid gzip request body")
+		}
+		defer gz.Close()
+		body = gz
+	}
+	body = io.LimitReader(body, maxDecodedBatchSize+1)
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == contentTypeNDJSON {
+		return decodeNDJSON(body)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, errorsx.New(errorsx.CodeBadRequest, "Failed to read request body")
+	}
+	if len(data) > maxDecodedBatchSize {
+		return nil, errorsx.New(errorsx.CodeBadRequest, "Request body too large")
+	}
+
+	var req model.CreateInstallEventBatchRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, errorsx.New(errorsx.CodeBadRequest, "Invalid JSON request body: "+err.Error())
+	}
+	return req.Events, nil
+}
+
+// decodeNDJSON 逐行解析 NDJSON，忽略空行，错误信息包含行号
+func decodeNDJSON(r io.Reader) ([]*model.CreateInstallEventRequest, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	var events []*model.CreateInstallEventRequest
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		if len(events) >= maxBatchEvents {
+			return nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Too many events in batch, maximum is %d", maxBatchEvents))
+		}
+
+		var event model.CreateInstallEventRequest
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Invalid JSON on line %d: %v", line, err))
+		}
+		events = append(events, &event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if stderrors.Is(err, bufio.ErrTooLong) {
+			return nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Line %d exceeds maximum size", line+1))
+		}
+		return nil, errorsx.New(errorsx.CodeBadRequest, "Failed to read request body")
+	}
+	return events, nil
+}
+
+// validateEventBatch 校验批量中的每个事件，字段错误以 events[i].field 为键返回
+func validateEventBatch(events []*model.CreateInstallEventRequest) error {
+	details := make(map[string]string)
+	for i, event := range events {
+		if event == nil {
+			details[fmt.Sprintf("events[%d]", i)] = "This field is required"
+			continue
+		}
+
+		var validationErr validator.ValidationErrors
+		if err := eventValidator.Struct(event); stderrors.As(err, &validationErr) {
+			for _, fieldErr := range validationErr {
+				details[fmt.Sprintf("events[%d].%s", i, fieldErr.Field())] = getValidationErrorMessage(fieldErr)
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return errorsx.New(errorsx.CodeValidationFailed, "Validation failed", details)
+	}
+	return nil
+}
+
+func newEventValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
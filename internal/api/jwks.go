@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/pkg/jwtx"
+	"go.uber.org/zap"
+)
+
+type JWKSController struct {
+	*BaseController
+}
+
+func NewJWKSController(base *BaseController) *JWKSController {
+	return &JWKSController{
+		BaseController: base,
+	}
+}
+
+// JWKS 发布当前的验签公钥，按 RFC 7517 格式直接输出（不包裹统一响应结构）
+func (jc *JWKSController) JWKS(c *gin.Context) {
+	keySet, err := jwtx.Default()
+	if err != nil {
+		jc.GetLogger(c).Error("failed to load jwt keys", zap.Error(err))
+		InternalError(c, "failed to load jwt keys")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keySet.JWKS())
+}
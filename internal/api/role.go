@@ -0,0 +1,108 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"go.uber.org/zap"
+)
+
+type RoleController struct {
+	*BaseController
+	rbacService *service.RBACService
+	denylist    *middleware.TokenDenylist
+}
+
+func NewRoleController(base *BaseController) *RoleController {
+	repo := repository.NewRepository(base.DB)
+	baseService := service.NewBaseService(repo, base.Cache, base.Logger)
+	return &RoleController{
+		BaseController: base,
+		rbacService:    service.NewRBACService(baseService),
+		denylist:       middleware.NewTokenDenylist(base.Cache),
+	}
+}
+
+func (rc *RoleController) List(c *gin.Context) {
+	roles, err := rc.rbacService.ListRoles(c.Request.Context())
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, roles)
+}
+
+func (rc *RoleController) GetUserRoles(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		BadRequest(c, "invalid user id")
+		return
+	}
+
+	roles, err := rc.rbacService.GetUserRoles(c.Request.Context(), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, roles)
+}
+
+func (rc *RoleController) Assign(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		BadRequest(c, "invalid user id")
+		return
+	}
+
+	var req model.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if req.Role == "" {
+		BadRequest(c, "role is required")
+		return
+	}
+
+	if err := rc.rbacService.AssignRole(c.Request.Context(), userID, req.Role); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	rc.expireUserTokens(c, userID)
+	rc.GetLogger(c).Info("role assigned", zap.Uint64("user_id", userID), zap.String("role", req.Role))
+	Success(c, nil)
+}
+
+func (rc *RoleController) Remove(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		BadRequest(c, "invalid user id")
+		return
+	}
+
+	role := c.Param("role")
+	if err := rc.rbacService.RemoveRole(c.Request.Context(), userID, role); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	rc.expireUserTokens(c, userID)
+	rc.GetLogger(c).Info("role removed", zap.Uint64("user_id", userID), zap.String("role", role))
+	Success(c, nil)
+}
+
+// expireUserTokens 角色变更后使旧访问令牌失效，客户端通过刷新令牌获取带新角色的令牌
+func (rc *RoleController) expireUserTokens(c *gin.Context, userID uint64) {
+	if err := rc.denylist.RevokeUser(c.Request.Context(), userID); err != nil {
+		rc.GetLogger(c).Error("failed to expire user tokens",
+			zap.Uint64("user_id", userID),
+			zap.Error(err))
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -8,12 +9,17 @@ import (
 	"github.com/iswangwenbin/gin-starter/internal/model"
 	"github.com/iswangwenbin/gin-starter/internal/repository"
 	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
 	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"go.uber.org/zap"
 )
 
 type UserController struct {
 	*BaseController
-	userService *service.UserService
+	userService  *service.UserService
+	tokenService *service.TokenService
+	rbacService  *service.RBACService
+	denylist     *middleware.TokenDenylist
 }
 
 func NewUserController(base *BaseController) *UserController {
@@ -22,6 +28,9 @@ func NewUserController(base *BaseController) *UserController {
 	return &UserController{
 		BaseController: base,
 		userService:    service.NewUserService(baseService),
+		tokenService:   service.NewTokenService(baseService),
+		rbacService:    service.NewRBACService(baseService),
+		denylist:       middleware.NewTokenDenylist(base.Cache),
 	}
 }
 
@@ -135,20 +144,126 @@ func (uc *UserController) Login(c *gin.Context) {
 	}
 
 	// 生成JWT token
-	token, err := middleware.GenerateToken(user.ID, user.Username)
+	token, err := uc.generateToken(c.Request.Context(), user)
 	if err != nil {
-		HandleError(c, errorsx.NewWithError(errorsx.CodeInternalServerError, "failed to generate token", err))
+		HandleError(c, err)
+		return
+	}
+
+	// 签发刷新令牌
+	refreshToken, err := uc.tokenService.IssueRefreshToken(user.ID, user.Username)
+	if err != nil {
+		HandleError(c, err)
 		return
 	}
 
 	response := model.LoginResponse{
-		Token: token,
-		User:  *user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    accessTokenExpiresIn(),
+		User:         *user,
 	}
 
 	Success(c, response)
 }
 
+func (uc *UserController) RefreshToken(c *gin.Context) {
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	// 轮换刷新令牌
+	session, refreshToken, err := uc.tokenService.RotateRefreshToken(req.RefreshToken)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	// 重新确认用户状态
+	user, err := uc.userService.GetByID(uint(session.UserID))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if user.Status != 1 {
+		if err := uc.tokenService.RevokeFamily(session.FamilyID); err != nil {
+			uc.GetLogger(c).Error("failed to revoke refresh token family", zap.Error(err))
+		}
+		HandleError(c, errorsx.ErrUserDisabled)
+		return
+	}
+
+	token, err := uc.generateToken(c.Request.Context(), user)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, model.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    accessTokenExpiresIn(),
+	})
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func (uc *UserController) Logout(c *gin.Context) {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		HandleError(c, errorsx.ErrTokenMissing)
+		return
+	}
+
+	// 请求体可选，携带刷新令牌时一并吊销
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	if err := uc.denylist.Revoke(c.Request.Context(), claims); err != nil {
+		HandleError(c, errorsx.NewWithError(errorsx.CodeRedisError, "failed to revoke token", err))
+		return
+	}
+
+	if err := uc.tokenService.RevokeRefreshToken(req.RefreshToken); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// RevokeTokens 吊销指定用户的所有访问令牌和刷新令牌
+func (uc *UserController) RevokeTokens(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		BadRequest(c, "invalid user id")
+		return
+	}
+
+	if err := uc.denylist.RevokeUser(c.Request.Context(), id); err != nil {
+		HandleError(c, errorsx.NewWithError(errorsx.CodeRedisError, "failed to revoke tokens", err))
+		return
+	}
+
+	if err := uc.tokenService.RevokeUserTokens(id); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	uc.GetLogger(c).Info("all tokens revoked for user", zap.Uint64("user_id", id))
+	Success(c, nil)
+}
+
 func (uc *UserController) Profile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -211,4 +326,27 @@ func (uc *UserController) ChangePassword(c *gin.Context) {
 	}
 
 	Success(c, nil)
-}
\ No newline at end of file
+}
+
+// generateToken 签发携带用户当前角色的访问令牌
+func (uc *UserController) generateToken(ctx context.Context, user *model.User) (string, error) {
+	roles, err := uc.rbacService.GetUserRoleNames(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := middleware.GenerateToken(user.ID, user.Username, roles)
+	if err != nil {
+		return "", errorsx.NewWithError(errorsx.CodeInternalServerError, "failed to generate token", err)
+	}
+	return token, nil
+}
+
+// accessTokenExpiresIn 访问令牌有效期（秒）
+func accessTokenExpiresIn() int64 {
+	cfg := configx.GetConfig()
+	if cfg == nil {
+		return 0
+	}
+	return int64(cfg.JWT.Expires.Seconds())
+}
@@ -32,13 +32,19 @@ func StartClickHouse(s *Server) error {
 	return nil
 }
 
+// TryClickHouse 按 clickhouse.enabled 连接 ClickHouse，启动时不可用只记录警告，事件查询和导出在恢复前返回错误
+func TryClickHouse(s *Server) error {
+	s.tryClickHouse = true
+	return nil
+}
+
 
 func WithDefaults() []Option {
-	return []Option{StartDatabase, StartCache, StartGRPC}
+	return []Option{StartDatabase, StartCache, TryClickHouse, StartGRPC}
 }
 
 func WithDebug() []Option {
-	return []Option{StartDatabase, StartCache, StartGRPC, StartDebug}
+	return []Option{StartDatabase, StartCache, TryClickHouse, StartGRPC, StartDebug}
 }
 
 func WithPProf() []Option {
@@ -46,7 +52,7 @@ func WithPProf() []Option {
 }
 
 func WithAll() []Option {
-	return []Option{StartDatabase, StartCache, StartClickHouse, StartGRPC, StartDebug, StartPProf}
+	return []Option{StartDatabase, StartCache, TryClickHouse, StartGRPC, StartDebug, StartPProf}
 }
 
 func WithHTTPOnly() []Option {
@@ -58,7 +64,7 @@ func WithGRPCOnly() []Option {
 }
 
 func WithClickHouse() []Option {
-	return []Option{StartDatabase, StartCache, StartClickHouse, StartGRPC}
+	return []Option{StartDatabase, StartCache, TryClickHouse, StartGRPC}
 }
 
 func WithWorker() []Option {
@@ -1,8 +1,13 @@
 package core
 
 import (
+	"context"
+
 	"github.com/iswangwenbin/gin-starter/internal/api"
 	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
 )
 
 // setupRoutes 设置路由
@@ -14,13 +19,25 @@ func (s *Server) setupRoutes() {
 	s.Engine.StaticFile("/favicon.ico", "./public/favicon.ico")
 
 	// 初始化控制器
-	baseController := api.NewBaseController(s.DB, s.Cache, s.logger)
+	baseController := api.NewBaseController(s.DB, s.Cache, s.ClickHouse, s.logger)
 	healthController := api.NewHealthController(baseController)
 	userController := api.NewUserController(baseController)
+	roleController := api.NewRoleController(baseController)
+	jwksController := api.NewJWKSController(baseController)
+	appController := api.NewAppController(baseController)
+	installEventController := api.NewInstallEventController(baseController)
+	go installEventController.RecoverExports(context.Background())
+	s.onShutdown(installEventController.ShutdownExports)
+	deadLetterController := api.NewDeadLetterController(baseController)
+	tokenDenylist := middleware.NewTokenDenylist(s.Cache)
+	baseService := service.NewBaseService(repository.NewRepository(s.DB), s.Cache, s.logger)
+	rbacService := service.NewRBACService(baseService)
+	appService := service.NewAppService(baseService)
 
 	// 基础路由
 	s.Engine.GET("/ping", healthController.Ping)
 	s.Engine.GET("/health", healthController.Check)
+	s.Engine.GET("/.well-known/jwks.json", jwksController.JWKS)
 
 	// API路由分组
 	apiV1 := s.Engine.Group("/api/v1")
@@ -34,24 +51,79 @@ func (s *Server) setupRoutes() {
 		{
 			authGroup.POST("/login", userController.Login)
 			authGroup.POST("/register", userController.Create)
+			authGroup.POST("/refresh", userController.RefreshToken)
+		}
+
+		// 安装事件上报，使用应用 HMAC 签名认证
+		appSigned := middleware.AppSignatureAuth(appService)
+		eventGroup := apiV1.Group("/install-events")
+		{
+			eventGroup.POST("", appSigned, installEventController.Create)
+			eventGroup.POST("/batch", appSigned, installEventController.CreateBatch)
 		}
 
 		// 需要认证的路由
 		authenticated := apiV1.Group("/")
-		authenticated.Use(middleware.JWTAuth())
+		authenticated.Use(middleware.JWTAuth(tokenDenylist))
 		{
+			// 登出
+			authenticated.POST("/auth/logout", userController.Logout)
+
 			// 用户个人信息
 			authenticated.GET("/profile", userController.Profile)
 			authenticated.PUT("/profile", userController.UpdateProfile)
 			authenticated.POST("/change-password", userController.ChangePassword)
 
 			// 用户管理路由（需要管理员权限）
+			canReadUsers := middleware.RequirePermission(rbacService, model.PermissionUsersRead)
+			canWriteUsers := middleware.RequirePermission(rbacService, model.PermissionUsersWrite)
+			canAssignRoles := middleware.RequirePermission(rbacService, model.PermissionRolesAssign)
+
 			userGroup := authenticated.Group("/users")
 			{
-				userGroup.GET("", userController.List)
-				userGroup.GET("/:id", userController.GetByID)
-				userGroup.PUT("/:id", userController.Update)
-				userGroup.DELETE("/:id", userController.Delete)
+				userGroup.GET("", canReadUsers, userController.List)
+				userGroup.GET("/:id", canReadUsers, userController.GetByID)
+				userGroup.PUT("/:id", canWriteUsers, userController.Update)
+				userGroup.DELETE("/:id", canWriteUsers, userController.Delete)
+				userGroup.POST("/:id/revoke-tokens", canWriteUsers, userController.RevokeTokens)
+
+				// 用户角色管理
+				userGroup.GET("/:id/roles", canReadUsers, roleController.GetUserRoles)
+				userGroup.POST("/:id/roles", canAssignRoles, roleController.Assign)
+				userGroup.DELETE("/:id/roles/:role", canAssignRoles, roleController.Remove)
+			}
+
+			authenticated.GET("/roles", canReadUsers, roleController.List)
+
+			// 事件上报应用及密钥管理
+			canManageApps := middleware.RequirePermission(rbacService, model.PermissionAppsManage)
+			appGroup := authenticated.Group("/apps", canManageApps)
+			{
+				appGroup.GET("", appController.List)
+				appGroup.POST("", appController.Create)
+				appGroup.GET("/:app_id", appController.Get)
+				appGroup.PUT("/:app_id/status", appController.UpdateStatus)
+				appGroup.POST("/:app_id/keys", appController.CreateKey)
+				appGroup.POST("/:app_id/keys/:key_id/rotate", appController.RotateKey)
+				appGroup.DELETE("/:app_id/keys/:key_id", appController.RevokeKey)
+			}
+
+			// 安装事件查询
+			canReadEvents := middleware.RequirePermission(rbacService, model.PermissionEventsRead)
+			authenticated.GET("/install-events", canReadEvents, installEventController.List)
+			authenticated.GET("/install-events/stats", canReadEvents, installEventController.Stats)
+			authenticated.GET("/install-events/timeseries", canReadEvents, installEventController.TimeSeries)
+			authenticated.POST("/install-events/exports", canReadEvents, installEventController.CreateExport)
+			authenticated.GET("/install-events/exports/:id", canReadEvents, installEventController.GetExport)
+			authenticated.GET("/install-events/exports/:id/download", canReadEvents, installEventController.DownloadExport)
+
+			// 安装事件死信管理
+			canManageEvents := middleware.RequirePermission(rbacService, model.PermissionEventsManage)
+			deadLetterGroup := authenticated.Group("/install-events/dead-letters", canManageEvents)
+			{
+				deadLetterGroup.GET("", deadLetterController.List)
+				deadLetterGroup.POST("/replay", deadLetterController.Replay)
+				deadLetterGroup.POST("/purge", deadLetterController.Purge)
 			}
 		}
 	}
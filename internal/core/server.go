@@ -20,7 +20,9 @@ import (
 	"github.com/iswangwenbin/gin-starter/pkg/clickhousex"
 	"github.com/iswangwenbin/gin-starter/pkg/configx"
 	"github.com/iswangwenbin/gin-starter/pkg/databasex"
+	"github.com/iswangwenbin/gin-starter/pkg/jwtx"
 	"github.com/iswangwenbin/gin-starter/pkg/redisx"
+	"github.com/iswangwenbin/gin-starter/pkg/tlsx"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -41,6 +43,9 @@ type Server struct {
 	startCache      bool // 是否初始化Redis
 	startGRPC       bool // 是否启动gRPC服务器
 	startClickHouse bool // 是否初始化ClickHouse
+	tryClickHouse   bool // 是否按配置初始化ClickHouse，不可用时降级
+
+	shutdownHooks []func(context.Context) error // HTTP 服务停止后依次执行
 }
 
 func NewServer(env string, options ...Option) (*Server, error) {
@@ -121,18 +126,50 @@ func (s *Server) initComponents() {
 	if s.startClickHouse {
 		s.ClickHouse = clickhousex.NewClickHouse()
 		s.logger.Info("ClickHouse Enable")
+	} else if s.tryClickHouse {
+		s.initOptionalClickHouse()
 	}
 
 	if s.startGRPC {
 		cfg := configx.GetConfig()
 		if cfg != nil && cfg.GRPC.Enabled {
-			s.GRPCServer = server.NewServer(cfg, s.logger, s.DB, s.Cache)
+			s.GRPCServer = server.NewServer(cfg, s.logger, s.DB, s.Cache, s.ClickHouse)
 			s.logger.Info("gRPC Server Enable")
 		}
 	}
 
 }
 
+// initOptionalClickHouse 未启用时不连接，事件查询和导出返回 503；
+// 启动时无法连接只记录警告，保留连接池以便 ClickHouse 恢复后自动可用，MySQL、Redis 相关功能不受影响
+func (s *Server) initOptionalClickHouse() {
+	cfg := configx.GetConfig()
+	if cfg == nil || !cfg.ClickHouse.Enabled {
+		s.logger.Info("ClickHouse Disabled")
+		return
+	}
+
+	conn, err := clickhousex.Open()
+	if err != nil {
+		s.logger.Warn("Failed to open ClickHouse, install event queries are disabled", zap.Error(err))
+		return
+	}
+	s.ClickHouse = conn
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := conn.Ping(ctx); err != nil {
+		s.logger.Warn("ClickHouse is unavailable, install event queries will fail until it recovers", zap.Error(err))
+		return
+	}
+	s.logger.Info("ClickHouse Enable")
+}
+
+// onShutdown 注册关闭时执行的清理函数，如等待后台任务结束
+func (s *Server) onShutdown(hook func(context.Context) error) {
+	s.shutdownHooks = append(s.shutdownHooks, hook)
+}
+
 // Logger 获取日志实例
 func (s *Server) Logger() *zap.Logger {
 	return s.logger
@@ -175,14 +212,15 @@ func (s *Server) listen() {
 	defer cancel()
 
 	var wg sync.WaitGroup
-	httpSrv := s.startHTTPServer(cfg, &wg)
+	httpSrv := s.startHTTPServer(ctx, cfg, &wg)
+	s.watchJWTKeys(ctx)
 	s.startGRPCServer(ctx, &wg)
 
 	s.logServerStatus(cfg)
 	s.handleShutdown(cancel, httpSrv, &wg)
 }
 
-func (s *Server) startHTTPServer(cfg *configx.Config, wg *sync.WaitGroup) *http.Server {
+func (s *Server) startHTTPServer(ctx context.Context, cfg *configx.Config, wg *sync.WaitGroup) *http.Server {
 	httpSrv := &http.Server{
 		Addr:           cfg.GetServerAddress(),
 		Handler:        s.Engine,
@@ -191,12 +229,35 @@ func (s *Server) startHTTPServer(cfg *configx.Config, wg *sync.WaitGroup) *http.
 		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
 	}
 
+	// TLS 证书变化时自动重新加载
+	if cfg.Server.TLS.Enabled {
+		reloader, err := tlsx.NewReloader(cfg.Server.TLS, s.logger)
+		if err != nil {
+			log.Fatalf("Failed to load HTTP TLS certificate: %v", err)
+		}
+		httpSrv.TLSConfig = reloader.TLSConfig("h2", "http/1.1")
+
+		go func() {
+			if err := reloader.Watch(ctx); err != nil {
+				s.logger.Error("HTTP TLS certificate watcher stopped", zap.Error(err))
+			}
+		}()
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		s.logger.Info("HTTP server starting", zap.String("address", httpSrv.Addr))
-
-		if err := httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Info("HTTP server starting",
+			zap.String("address", httpSrv.Addr),
+			zap.Bool("tls", httpSrv.TLSConfig != nil))
+
+		var err error
+		if httpSrv.TLSConfig != nil {
+			err = httpSrv.ListenAndServeTLS("", "")
+		} else {
+			err = httpSrv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.logger.Error("HTTP server error", zap.Error(err))
 		}
 	}()
@@ -204,6 +265,20 @@ func (s *Server) startHTTPServer(cfg *configx.Config, wg *sync.WaitGroup) *http.
 	return httpSrv
 }
 
+// watchJWTKeys 配置文件或密钥文件变化时重新加载 JWT 密钥，支持不停机轮换
+func (s *Server) watchJWTKeys(ctx context.Context) {
+	configPath := configx.ConfigFile()
+	if configPath == "" {
+		return
+	}
+
+	go func() {
+		if err := jwtx.Watch(ctx, configPath, s.logger); err != nil {
+			s.logger.Error("JWT key watcher stopped", zap.Error(err))
+		}
+	}()
+}
+
 func (s *Server) startGRPCServer(ctx context.Context, wg *sync.WaitGroup) {
 	if s.GRPCServer == nil {
 		return
@@ -244,6 +319,12 @@ func (s *Server) handleShutdown(cancel context.CancelFunc, httpSrv *http.Server,
 		s.logger.Info("HTTP server shut down successfully")
 	}
 
+	for _, hook := range s.shutdownHooks {
+		if err := hook(shutdownCtx); err != nil {
+			s.logger.Error("Shutdown hook error", zap.Error(err))
+		}
+	}
+
 	done := make(chan struct{})
 	go func() {
 		wg.Wait()
@@ -2,44 +2,32 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
 	"go.uber.org/zap"
 )
 
 // ClientManager gRPC 客户端管理器
 type ClientManager struct {
-	address    string
-	conn       *grpc.ClientConn
-	logger     *zap.Logger
-	userClient *UserClient
+	target       string
+	conn         *grpc.ClientConn
+	logger       *zap.Logger
+	userClient   *UserClient
 	healthClient protobuf.HealthServiceClient
 }
 
 // NewClientManager 创建 gRPC 客户端管理器
-func NewClientManager(address string, logger *zap.Logger) (*ClientManager, error) {
-	// 连接选项
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	}
-
-	// 创建连接
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	conn, err := grpc.DialContext(ctx, address, opts...)
+func NewClientManager(cfg configx.GRPCClientConfig, logger *zap.Logger) (*ClientManager, error) {
+	conn, err := newConn(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+		return nil, err
 	}
 
 	return &ClientManager{
-		address:      address,
+		target:       conn.Target(),
 		conn:         conn,
 		logger:       logger,
 		healthClient: protobuf.NewHealthServiceClient(conn),
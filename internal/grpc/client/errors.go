@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"google.golang.org/grpc"
+)
+
+// errorUnaryInterceptor 将服务端返回的 gRPC 状态还原为 *errorsx.AppError
+func errorUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return errorsx.FromGRPC(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// errorStreamInterceptor 流式调用建立阶段的错误转换
+func errorStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, errorsx.FromGRPC(err)
+		}
+		return stream, nil
+	}
+}
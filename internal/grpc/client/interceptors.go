@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iswangwenbin/gin-starter/internal/grpc/interceptor"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	bearerTokenContextKey
+)
+
+var requestIDMetadataKey = strings.ToLower(middleware.RequestIDKey)
+
+// WithRequestID 指定调用携带的请求 ID，未指定时沿用服务端入站请求 ID 或自动生成
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// WithBearerToken 指定调用携带的访问令牌，写入 authorization metadata
+func WithBearerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, bearerTokenContextKey, token)
+}
+
+// metadataUnaryInterceptor 传播请求 ID 和访问令牌
+func metadataUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingMetadata(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// metadataStreamInterceptor 流式调用的请求 ID 和访问令牌传播
+func metadataStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingMetadata(ctx), desc, cc, method, opts...)
+	}
+}
+
+// timeoutUnaryInterceptor 调用未设置截止时间时使用默认超时
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// withOutgoingMetadata 补充出站 metadata，已显式设置的值不覆盖
+func withOutgoingMetadata(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	if len(md.Get(requestIDMetadataKey)) == 0 {
+		requestID, _ := ctx.Value(requestIDContextKey).(string)
+		if requestID == "" {
+			requestID = interceptor.RequestIDFromContext(ctx)
+		}
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, requestID)
+	}
+
+	if len(md.Get("authorization")) == 0 {
+		if token, _ := ctx.Value(bearerTokenContextKey).(string); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+	}
+
+	return ctx
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const (
+	defaultTimeout        = 5 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+	maxRetryAttempts      = 5 // gRPC 限制的最大尝试次数
+	staticResolverScheme  = "static"
+)
+
+// methodName 服务配置中的方法名，Method 为空表示整个服务
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+// idempotentMethods 可安全重试的幂等方法
+var idempotentMethods = []methodName{
+	{Service: "user.UserService", Method: "GetUser"},
+	{Service: "user.UserService", Method: "ListUsers"},
+	{Service: "protobuf.InstallEventService", Method: "ListInstallEvents"},
+	{Service: "protobuf.InstallEventService", Method: "GetInstallStats"},
+	{Service: "protobuf.InstallEventService", Method: "GetInstallTimeSeries"},
+	{Service: "common.HealthService"},
+	{Service: "grpc.health.v1.Health"},
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig"`
+	MethodConfig        []methodConfig        `json:"methodConfig"`
+}
+
+// withDefaults 补全未配置的客户端参数
+func withDefaults(cfg configx.GRPCClientConfig) configx.GRPCClientConfig {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.MaxAttempts > maxRetryAttempts {
+		cfg.MaxAttempts = maxRetryAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	return cfg
+}
+
+// buildServiceConfig 轮询负载均衡，并对幂等方法在 UNAVAILABLE 时指数退避重试
+func buildServiceConfig(cfg configx.GRPCClientConfig) (string, error) {
+	sc := serviceConfig{
+		LoadBalancingConfig: []map[string]struct{}{{"round_robin": {}}},
+	}
+	if cfg.MaxAttempts > 1 {
+		sc.MethodConfig = []methodConfig{{
+			Name: idempotentMethods,
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          cfg.MaxAttempts,
+				InitialBackoff:       formatDuration(cfg.InitialBackoff),
+				MaxBackoff:           formatDuration(cfg.MaxBackoff),
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// formatDuration 转换为服务配置要求的秒数格式，如 0.100s
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+// buildTarget 配置了静态地址列表时使用手动 resolver，否则直接使用 target（支持 dns:///）
+func buildTarget(cfg configx.GRPCClientConfig) (string, []grpc.DialOption, error) {
+	if len(cfg.Addresses) == 0 {
+		if cfg.Target == "" {
+			return "", nil, errors.New("grpc client target or addresses must be configured")
+		}
+		return cfg.Target, nil, nil
+	}
+
+	addresses := make([]resolver.Address, 0, len(cfg.Addresses))
+	for _, addr := range cfg.Addresses {
+		addresses = append(addresses, resolver.Address{Addr: addr})
+	}
+
+	r := manual.NewBuilderWithScheme(staticResolverScheme)
+	r.InitialState(resolver.State{Addresses: addresses})
+
+	return staticResolverScheme + ":///gin-starter", []grpc.DialOption{grpc.WithResolvers(r)}, nil
+}
+
+// buildCredentials 根据配置创建传输凭据
+func buildCredentials(cfg configx.ClientTLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("failed to parse CA certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// newConn 按配置创建连接，连接在首次调用时建立，不阻塞
+func newConn(cfg configx.GRPCClientConfig) (*grpc.ClientConn, error) {
+	cfg = withDefaults(cfg)
+
+	target, opts, err := buildTarget(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := buildCredentials(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := buildServiceConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build service config: %w", err)
+	}
+
+	opts = append(opts,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(sc),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                30 * time.Second,
+			Timeout:             5 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithChainUnaryInterceptor(
+			metadataUnaryInterceptor(),
+			timeoutUnaryInterceptor(cfg.Timeout),
+			errorUnaryInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			metadataStreamInterceptor(),
+			errorStreamInterceptor(),
+		),
+	)
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	return conn, nil
+}
@@ -2,13 +2,11 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
 	"go.uber.org/zap"
 )
 
@@ -20,27 +18,14 @@ type UserClient struct {
 }
 
 // NewUserClient 创建用户服务的 gRPC 客户端
-func NewUserClient(address string, logger *zap.Logger) (*UserClient, error) {
-	// 连接选项
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	}
-
-	// 创建连接
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	conn, err := grpc.DialContext(ctx, address, opts...)
+func NewUserClient(cfg configx.GRPCClientConfig, logger *zap.Logger) (*UserClient, error) {
+	conn, err := newConn(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+		return nil, err
 	}
 
-	// 创建客户端
-	client := protobuf.NewUserServiceClient(conn)
-
 	return &UserClient{
-		client: client,
+		client: protobuf.NewUserServiceClient(conn),
 		conn:   conn,
 		logger: logger,
 	}, nil
@@ -0,0 +1,91 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Allowlist 无需 JWT 认证的方法，元素为完整方法名（如 /user.UserService/Login）
+// 或以 / 结尾的服务前缀（如 /protobuf.InstallEventService/）
+type Allowlist []string
+
+// Allows 判断方法是否免认证
+func (a Allowlist) Allows(fullMethod string) bool {
+	for _, entry := range a {
+		if entry == fullMethod {
+			return true
+		}
+		if strings.HasSuffix(entry, "/") && strings.HasPrefix(fullMethod, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthUnary 校验 authorization metadata 中的 Bearer 令牌，复用 HTTP 的 JWT 解析和吊销检查
+func AuthUnary(denylist *middleware.TokenDenylist, allowlist Allowlist, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if allowlist.Allows(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		newCtx, err := authenticate(ctx, denylist, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+// AuthStream 流式调用的 JWT 认证拦截器
+func AuthStream(denylist *middleware.TokenDenylist, allowlist Allowlist, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if allowlist.Allows(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		newCtx, err := authenticate(ss.Context(), denylist, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, wrapStream(ss, newCtx))
+	}
+}
+
+func authenticate(ctx context.Context, denylist *middleware.TokenDenylist, logger *zap.Logger) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	parts := strings.SplitN(values[0], " ", 2)
+	if !(len(parts) == 2 && parts[0] == "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+	}
+
+	claims, err := middleware.ParseToken(parts[1])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	revoked, err := denylist.IsRevoked(ctx, claims)
+	if err != nil {
+		logger.Error("token revocation check failed",
+			zap.String("request_id", RequestIDFromContext(ctx)),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "token revocation check failed")
+	}
+	if revoked {
+		return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+	}
+
+	return withClaims(ctx, claims), nil
+}
@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"google.golang.org/grpc"
+)
+
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	claimsContextKey
+	accessLogContextKey
+)
+
+// accessLog 由访问日志拦截器放入 context，后续拦截器写入的字段在调用结束后记录；
+// 访问日志在认证之前执行，以便记录被拒绝的调用，认证结果只能通过该可变对象传回
+type accessLog struct {
+	claims *middleware.Claims
+}
+
+// RequestIDFromContext 获取当前调用的请求 ID
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// ClaimsFromContext 获取认证通过后的 JWT Claims，未认证时返回 nil
+func ClaimsFromContext(ctx context.Context) *middleware.Claims {
+	claims, _ := ctx.Value(claimsContextKey).(*middleware.Claims)
+	return claims
+}
+
+// withClaims 保存认证通过的 Claims，并写入访问日志
+func withClaims(ctx context.Context, claims *middleware.Claims) context.Context {
+	if log, ok := ctx.Value(accessLogContextKey).(*accessLog); ok {
+		log.claims = claims
+	}
+	return context.WithValue(ctx, claimsContextKey, claims)
+}
+
+// ContextWithClaims 返回带有 Claims 的 context，用于进程内调用或测试中模拟认证通过的请求
+func ContextWithClaims(ctx context.Context, claims *middleware.Claims) context.Context {
+	return withClaims(ctx, claims)
+}
+
+// wrappedStream 替换流式调用的 context
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+func wrapStream(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	if w, ok := ss.(*wrappedStream); ok {
+		w.ctx = ctx
+		return w
+	}
+	return &wrappedStream{ServerStream: ss, ctx: ctx}
+}
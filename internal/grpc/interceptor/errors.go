@@ -0,0 +1,23 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"google.golang.org/grpc"
+)
+
+// ErrorUnary 将 handler 返回的 errorsx.AppError 转换为带 google.rpc 详情的 gRPC 状态
+func ErrorUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, errorsx.ToGRPC(err)
+	}
+}
+
+// ErrorStream 流式调用的错误转换拦截器
+func ErrorStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return errorsx.ToGRPC(handler(srv, ss))
+	}
+}
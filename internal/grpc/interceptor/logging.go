@@ -0,0 +1,63 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingUnary 记录 gRPC 访问日志，字段与 HTTP 的 ginzap 日志保持一致
+func LoggingUnary(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		log := &accessLog{}
+		ctx = context.WithValue(ctx, accessLogContextKey, log)
+		resp, err := handler(ctx, req)
+		logAccess(ctx, logger, log, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStream 流式调用的访问日志拦截器，在流结束时记录
+func LoggingStream(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		log := &accessLog{}
+		ctx := context.WithValue(ss.Context(), accessLogContextKey, log)
+		err := handler(srv, wrapStream(ss, ctx))
+		logAccess(ctx, logger, log, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logAccess(ctx context.Context, logger *zap.Logger, log *accessLog, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("latency", time.Since(start)),
+		zap.String("request_id", RequestIDFromContext(ctx)),
+		zap.String("time", start.Format(time.RFC3339)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("ip", p.Addr.String()))
+	}
+	if log.claims != nil {
+		fields = append(fields, zap.Uint64("user_id", log.claims.UserID))
+	}
+
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		logger.Error(method, append(fields, zap.Error(err))...)
+	default:
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		logger.Info(method, fields...)
+	}
+}
@@ -0,0 +1,80 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+)
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+func TestLoggingRecordsClaimsSetByLaterInterceptor(t *testing.T) {
+	claims := &middleware.Claims{UserID: 42}
+	tests := []struct {
+		name         string
+		authenticate bool
+		wantUserID   bool
+	}{
+		{name: "authenticated", authenticate: true, wantUserID: true},
+		{name: "anonymous", authenticate: false, wantUserID: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			logger := zap.New(core)
+			auth := func(ctx context.Context) context.Context {
+				if tt.authenticate {
+					return withClaims(ctx, claims)
+				}
+				return ctx
+			}
+
+			unary := LoggingUnary(logger)
+			_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Unary"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					ctx = auth(ctx)
+					if got := ClaimsFromContext(ctx) != nil; got != tt.authenticate {
+						t.Errorf("ClaimsFromContext() present = %v, want %v", got, tt.authenticate)
+					}
+					return nil, nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stream := LoggingStream(logger)
+			err = stream(nil, &testStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					auth(ss.Context())
+					return nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			entries := logs.All()
+			if len(entries) != 2 {
+				t.Fatalf("got %d log entries, want 2", len(entries))
+			}
+			for _, entry := range entries {
+				userID, ok := entry.ContextMap()["user_id"]
+				if ok != tt.wantUserID {
+					t.Errorf("%s: user_id logged = %v, want %v", entry.Message, ok, tt.wantUserID)
+				}
+				if ok && userID != uint64(42) {
+					t.Errorf("%s: user_id = %v, want 42", entry.Message, userID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnary 捕获 handler 中的 panic 并返回 Internal，避免进程崩溃
+func RecoveryUnary(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(ctx, logger, info.FullMethod, recovered)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStream 流式调用的 panic 恢复拦截器
+func RecoveryStream(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(ss.Context(), logger, info.FullMethod, recovered)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recoverPanic(ctx context.Context, logger *zap.Logger, method string, recovered interface{}) error {
+	logger.Error("panic recovered",
+		zap.Any("error", recovered),
+		zap.String("request_id", RequestIDFromContext(ctx)),
+		zap.String("method", method),
+		zap.String("stack", string(debug.Stack())),
+	)
+	return status.Error(codes.Internal, "internal server error")
+}
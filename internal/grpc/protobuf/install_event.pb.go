@@ -323,8 +323,10 @@ type CreateInstallEventBatchResponse struct {
 	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	ProcessedCount int32                  `protobuf:"varint,3,opt,name=processed_count,json=processedCount,proto3" json:"processed_count,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// 去重窗口内已收到而未入队的 event_id
+	DuplicateEventIds []string `protobuf:"bytes,4,rep,name=duplicate_event_ids,json=duplicateEventIds,proto3" json:"duplicate_event_ids,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CreateInstallEventBatchResponse) Reset() {
@@ -378,6 +380,974 @@ func (x *CreateInstallEventBatchResponse) GetProcessedCount() int32 {
 	return 0
 }
 
+func (x *CreateInstallEventBatchResponse) GetDuplicateEventIds() []string {
+	if x != nil {
+		return x.DuplicateEventIds
+	}
+	return nil
+}
+
+// 查询安装事件请求，时间范围为 [start_time, end_time)
+type ListInstallEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	AppType       *uint32                `protobuf:"varint,2,opt,name=app_type,json=appType,proto3,oneof" json:"app_type,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	ChannelId     string                 `protobuf:"bytes,4,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	InstallType   *uint32                `protobuf:"varint,5,opt,name=install_type,json=installType,proto3,oneof" json:"install_type,omitempty"`
+	InstallResult *uint32                `protobuf:"varint,6,opt,name=install_result,json=installResult,proto3,oneof" json:"install_result,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize      int32                  `protobuf:"varint,9,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// 上一页返回的 next_cursor，首页为空
+	Cursor        string `protobuf:"bytes,10,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstallEventsRequest) Reset() {
+	*x = ListInstallEventsRequest{}
+	mi := &file_install_event_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstallEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstallEventsRequest) ProtoMessage() {}
+
+func (x *ListInstallEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstallEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListInstallEventsRequest) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListInstallEventsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *ListInstallEventsRequest) GetAppType() uint32 {
+	if x != nil && x.AppType != nil {
+		return *x.AppType
+	}
+	return 0
+}
+
+func (x *ListInstallEventsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ListInstallEventsRequest) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *ListInstallEventsRequest) GetInstallType() uint32 {
+	if x != nil && x.InstallType != nil {
+		return *x.InstallType
+	}
+	return 0
+}
+
+func (x *ListInstallEventsRequest) GetInstallResult() uint32 {
+	if x != nil && x.InstallResult != nil {
+		return *x.InstallResult
+	}
+	return 0
+}
+
+func (x *ListInstallEventsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListInstallEventsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListInstallEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListInstallEventsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// 安装事件
+type InstallEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AppId            string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	AppName          string                 `protobuf:"bytes,2,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	AppVersion       string                 `protobuf:"bytes,3,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
+	AppType          uint32                 `protobuf:"varint,4,opt,name=app_type,json=appType,proto3" json:"app_type,omitempty"`
+	EventId          string                 `protobuf:"bytes,5,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventTime        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	DeviceId         string                 `protobuf:"bytes,7,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	ChannelId        string                 `protobuf:"bytes,8,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	InstallIp        string                 `protobuf:"bytes,9,opt,name=install_ip,json=installIp,proto3" json:"install_ip,omitempty"`
+	InstallType      uint32                 `protobuf:"varint,10,opt,name=install_type,json=installType,proto3" json:"install_type,omitempty"`
+	InstallResult    uint32                 `protobuf:"varint,11,opt,name=install_result,json=installResult,proto3" json:"install_result,omitempty"`
+	OsLanguage       string                 `protobuf:"bytes,12,opt,name=os_language,json=osLanguage,proto3" json:"os_language,omitempty"`
+	OsTimezone       string                 `protobuf:"bytes,13,opt,name=os_timezone,json=osTimezone,proto3" json:"os_timezone,omitempty"`
+	OsName           string                 `protobuf:"bytes,14,opt,name=os_name,json=osName,proto3" json:"os_name,omitempty"`
+	OsVersion        string                 `protobuf:"bytes,15,opt,name=os_version,json=osVersion,proto3" json:"os_version,omitempty"`
+	OsBuild          string                 `protobuf:"bytes,16,opt,name=os_build,json=osBuild,proto3" json:"os_build,omitempty"`
+	OsFamily         string                 `protobuf:"bytes,17,opt,name=os_family,json=osFamily,proto3" json:"os_family,omitempty"`
+	SignatureStatus  uint32                 `protobuf:"varint,18,opt,name=signature_status,json=signatureStatus,proto3" json:"signature_status,omitempty"`
+	SignatureVersion string                 `protobuf:"bytes,19,opt,name=signature_version,json=signatureVersion,proto3" json:"signature_version,omitempty"`
+	SignatureParams  map[string]string      `protobuf:"bytes,20,rep,name=signature_params,json=signatureParams,proto3" json:"signature_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *InstallEvent) Reset() {
+	*x = InstallEvent{}
+	mi := &file_install_event_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallEvent) ProtoMessage() {}
+
+func (x *InstallEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallEvent.ProtoReflect.Descriptor instead.
+func (*InstallEvent) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InstallEvent) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetAppVersion() string {
+	if x != nil {
+		return x.AppVersion
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetAppType() uint32 {
+	if x != nil {
+		return x.AppType
+	}
+	return 0
+}
+
+func (x *InstallEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *InstallEvent) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetInstallIp() string {
+	if x != nil {
+		return x.InstallIp
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetInstallType() uint32 {
+	if x != nil {
+		return x.InstallType
+	}
+	return 0
+}
+
+func (x *InstallEvent) GetInstallResult() uint32 {
+	if x != nil {
+		return x.InstallResult
+	}
+	return 0
+}
+
+func (x *InstallEvent) GetOsLanguage() string {
+	if x != nil {
+		return x.OsLanguage
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetOsTimezone() string {
+	if x != nil {
+		return x.OsTimezone
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetOsName() string {
+	if x != nil {
+		return x.OsName
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetOsVersion() string {
+	if x != nil {
+		return x.OsVersion
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetOsBuild() string {
+	if x != nil {
+		return x.OsBuild
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetOsFamily() string {
+	if x != nil {
+		return x.OsFamily
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetSignatureStatus() uint32 {
+	if x != nil {
+		return x.SignatureStatus
+	}
+	return 0
+}
+
+func (x *InstallEvent) GetSignatureVersion() string {
+	if x != nil {
+		return x.SignatureVersion
+	}
+	return ""
+}
+
+func (x *InstallEvent) GetSignatureParams() map[string]string {
+	if x != nil {
+		return x.SignatureParams
+	}
+	return nil
+}
+
+// 查询安装事件响应
+type ListInstallEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*InstallEvent        `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	HasMore       bool                   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstallEventsResponse) Reset() {
+	*x = ListInstallEventsResponse{}
+	mi := &file_install_event_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstallEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstallEventsResponse) ProtoMessage() {}
+
+func (x *ListInstallEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstallEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListInstallEventsResponse) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListInstallEventsResponse) GetEvents() []*InstallEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListInstallEventsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListInstallEventsResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// 安装统计请求，时间范围为 [start_time, end_time)
+type GetInstallStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	AppType       *uint32                `protobuf:"varint,2,opt,name=app_type,json=appType,proto3,oneof" json:"app_type,omitempty"`
+	ChannelId     string                 `protobuf:"bytes,3,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInstallStatsRequest) Reset() {
+	*x = GetInstallStatsRequest{}
+	mi := &file_install_event_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstallStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstallStatsRequest) ProtoMessage() {}
+
+func (x *GetInstallStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstallStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetInstallStatsRequest) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetInstallStatsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *GetInstallStatsRequest) GetAppType() uint32 {
+	if x != nil && x.AppType != nil {
+		return *x.AppType
+	}
+	return 0
+}
+
+func (x *GetInstallStatsRequest) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *GetInstallStatsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetInstallStatsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+// 渠道安装数
+type ChannelInstallStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChannelId     string                 `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChannelInstallStats) Reset() {
+	*x = ChannelInstallStats{}
+	mi := &file_install_event_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelInstallStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelInstallStats) ProtoMessage() {}
+
+func (x *ChannelInstallStats) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelInstallStats.ProtoReflect.Descriptor instead.
+func (*ChannelInstallStats) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChannelInstallStats) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *ChannelInstallStats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 平台安装数
+type AppTypeInstallStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppType       uint32                 `protobuf:"varint,1,opt,name=app_type,json=appType,proto3" json:"app_type,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppTypeInstallStats) Reset() {
+	*x = AppTypeInstallStats{}
+	mi := &file_install_event_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppTypeInstallStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppTypeInstallStats) ProtoMessage() {}
+
+func (x *AppTypeInstallStats) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppTypeInstallStats.ProtoReflect.Descriptor instead.
+func (*AppTypeInstallStats) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AppTypeInstallStats) GetAppType() uint32 {
+	if x != nil {
+		return x.AppType
+	}
+	return 0
+}
+
+func (x *AppTypeInstallStats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 安装统计响应
+type GetInstallStatsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TotalEvents      int64                  `protobuf:"varint,1,opt,name=total_events,json=totalEvents,proto3" json:"total_events,omitempty"`
+	SuccessEvents    int64                  `protobuf:"varint,2,opt,name=success_events,json=successEvents,proto3" json:"success_events,omitempty"`
+	FailedEvents     int64                  `protobuf:"varint,3,opt,name=failed_events,json=failedEvents,proto3" json:"failed_events,omitempty"`
+	SuccessRate      float64                `protobuf:"fixed64,4,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	FirstInstalls    int64                  `protobuf:"varint,5,opt,name=first_installs,json=firstInstalls,proto3" json:"first_installs,omitempty"`
+	RepeatInstalls   int64                  `protobuf:"varint,6,opt,name=repeat_installs,json=repeatInstalls,proto3" json:"repeat_installs,omitempty"`
+	UniqueDevices    int64                  `protobuf:"varint,7,opt,name=unique_devices,json=uniqueDevices,proto3" json:"unique_devices,omitempty"`
+	TopChannels      []*ChannelInstallStats `protobuf:"bytes,8,rep,name=top_channels,json=topChannels,proto3" json:"top_channels,omitempty"`
+	AppTypeBreakdown []*AppTypeInstallStats `protobuf:"bytes,9,rep,name=app_type_breakdown,json=appTypeBreakdown,proto3" json:"app_type_breakdown,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetInstallStatsResponse) Reset() {
+	*x = GetInstallStatsResponse{}
+	mi := &file_install_event_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstallStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstallStatsResponse) ProtoMessage() {}
+
+func (x *GetInstallStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstallStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetInstallStatsResponse) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetInstallStatsResponse) GetTotalEvents() int64 {
+	if x != nil {
+		return x.TotalEvents
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetSuccessEvents() int64 {
+	if x != nil {
+		return x.SuccessEvents
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetFailedEvents() int64 {
+	if x != nil {
+		return x.FailedEvents
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetFirstInstalls() int64 {
+	if x != nil {
+		return x.FirstInstalls
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetRepeatInstalls() int64 {
+	if x != nil {
+		return x.RepeatInstalls
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetUniqueDevices() int64 {
+	if x != nil {
+		return x.UniqueDevices
+	}
+	return 0
+}
+
+func (x *GetInstallStatsResponse) GetTopChannels() []*ChannelInstallStats {
+	if x != nil {
+		return x.TopChannels
+	}
+	return nil
+}
+
+func (x *GetInstallStatsResponse) GetAppTypeBreakdown() []*AppTypeInstallStats {
+	if x != nil {
+		return x.AppTypeBreakdown
+	}
+	return nil
+}
+
+// 安装趋势请求，granularity 为 minute/hour/day/week，group_by 为 app_id/channel_id/app_type/os_name，
+// timezone 为 IANA 时区名，默认 UTC
+type GetInstallTimeSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	AppType       *uint32                `protobuf:"varint,2,opt,name=app_type,json=appType,proto3,oneof" json:"app_type,omitempty"`
+	ChannelId     string                 `protobuf:"bytes,3,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Granularity   string                 `protobuf:"bytes,6,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	GroupBy       string                 `protobuf:"bytes,7,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Timezone      string                 `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInstallTimeSeriesRequest) Reset() {
+	*x = GetInstallTimeSeriesRequest{}
+	mi := &file_install_event_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstallTimeSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstallTimeSeriesRequest) ProtoMessage() {}
+
+func (x *GetInstallTimeSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstallTimeSeriesRequest.ProtoReflect.Descriptor instead.
+func (*GetInstallTimeSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetInstallTimeSeriesRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesRequest) GetAppType() uint32 {
+	if x != nil && x.AppType != nil {
+		return *x.AppType
+	}
+	return 0
+}
+
+func (x *GetInstallTimeSeriesRequest) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetInstallTimeSeriesRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetInstallTimeSeriesRequest) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+// 单个时间桶的指标
+type InstallMetricPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Success       int64                  `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	SuccessRate   float64                `protobuf:"fixed64,4,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallMetricPoint) Reset() {
+	*x = InstallMetricPoint{}
+	mi := &file_install_event_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallMetricPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallMetricPoint) ProtoMessage() {}
+
+func (x *InstallMetricPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallMetricPoint.ProtoReflect.Descriptor instead.
+func (*InstallMetricPoint) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InstallMetricPoint) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *InstallMetricPoint) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *InstallMetricPoint) GetSuccess() int64 {
+	if x != nil {
+		return x.Success
+	}
+	return 0
+}
+
+func (x *InstallMetricPoint) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+// 一个分组的时间序列
+type InstallTimeSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Points        []*InstallMetricPoint  `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallTimeSeries) Reset() {
+	*x = InstallTimeSeries{}
+	mi := &file_install_event_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallTimeSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallTimeSeries) ProtoMessage() {}
+
+func (x *InstallTimeSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallTimeSeries.ProtoReflect.Descriptor instead.
+func (*InstallTimeSeries) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *InstallTimeSeries) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *InstallTimeSeries) GetPoints() []*InstallMetricPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// 安装趋势响应
+type GetInstallTimeSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Granularity   string                 `protobuf:"bytes,1,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	GroupBy       string                 `protobuf:"bytes,2,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Timezone      string                 `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Series        []*InstallTimeSeries   `protobuf:"bytes,4,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInstallTimeSeriesResponse) Reset() {
+	*x = GetInstallTimeSeriesResponse{}
+	mi := &file_install_event_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstallTimeSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstallTimeSeriesResponse) ProtoMessage() {}
+
+func (x *GetInstallTimeSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_install_event_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstallTimeSeriesResponse.ProtoReflect.Descriptor instead.
+func (*GetInstallTimeSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_install_event_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetInstallTimeSeriesResponse) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesResponse) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesResponse) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetInstallTimeSeriesResponse) GetSeries() []*InstallTimeSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
 var File_install_event_proto protoreflect.FileDescriptor
 
 const file_install_event_proto_rawDesc = "" +
@@ -419,14 +1389,123 @@ const file_install_event_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"]\n" +
 	"\x1eCreateInstallEventBatchRequest\x12;\n" +
-	"\x06events\x18\x01 \x03(\v2#.protobuf.CreateInstallEventRequestR\x06events\"~\n" +
+	"\x06events\x18\x01 \x03(\v2#.protobuf.CreateInstallEventRequestR\x06events\"\xae\x01\n" +
 	"\x1fCreateInstallEventBatchResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
-	"\x0fprocessed_count\x18\x03 \x01(\x05R\x0eprocessedCount2\xe6\x01\n" +
+	"\x0fprocessed_count\x18\x03 \x01(\x05R\x0eprocessedCount\x12.\n" +
+	"\x13duplicate_event_ids\x18\x04 \x03(\tR\x11duplicateEventIds\"\xb9\x03\n" +
+	"\x18ListInstallEventsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x1e\n" +
+	"\bapp_type\x18\x02 \x01(\rH\x00R\aappType\x88\x01\x01\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\x04 \x01(\tR\tchannelId\x12&\n" +
+	"\finstall_type\x18\x05 \x01(\rH\x01R\vinstallType\x88\x01\x01\x12*\n" +
+	"\x0einstall_result\x18\x06 \x01(\rH\x02R\rinstallResult\x88\x01\x01\x129\n" +
+	"\n" +
+	"start_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\t \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\n" +
+	" \x01(\tR\x06cursorB\v\n" +
+	"\t_app_typeB\x0f\n" +
+	"\r_install_typeB\x11\n" +
+	"\x0f_install_result\"\x9d\x06\n" +
+	"\fInstallEvent\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x19\n" +
+	"\bapp_name\x18\x02 \x01(\tR\aappName\x12\x1f\n" +
+	"\vapp_version\x18\x03 \x01(\tR\n" +
+	"appVersion\x12\x19\n" +
+	"\bapp_type\x18\x04 \x01(\rR\aappType\x12\x19\n" +
+	"\bevent_id\x18\x05 \x01(\tR\aeventId\x129\n" +
+	"\n" +
+	"event_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\teventTime\x12\x1b\n" +
+	"\tdevice_id\x18\a \x01(\tR\bdeviceId\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\b \x01(\tR\tchannelId\x12\x1d\n" +
+	"\n" +
+	"install_ip\x18\t \x01(\tR\tinstallIp\x12!\n" +
+	"\finstall_type\x18\n" +
+	" \x01(\rR\vinstallType\x12%\n" +
+	"\x0einstall_result\x18\v \x01(\rR\rinstallResult\x12\x1f\n" +
+	"\vos_language\x18\f \x01(\tR\n" +
+	"osLanguage\x12\x1f\n" +
+	"\vos_timezone\x18\r \x01(\tR\n" +
+	"osTimezone\x12\x17\n" +
+	"\aos_name\x18\x0e \x01(\tR\x06osName\x12\x1d\n" +
+	"\n" +
+	"os_version\x18\x0f \x01(\tR\tosVersion\x12\x19\n" +
+	"\bos_build\x18\x10 \x01(\tR\aosBuild\x12\x1b\n" +
+	"\tos_family\x18\x11 \x01(\tR\bosFamily\x12)\n" +
+	"\x10signature_status\x18\x12 \x01(\rR\x0fsignatureStatus\x12+\n" +
+	"\x11signature_version\x18\x13 \x01(\tR\x10signatureVersion\x12V\n" +
+	"\x10signature_params\x18\x14 \x03(\v2+.protobuf.InstallEvent.SignatureParamsEntryR\x0fsignatureParams\x1aB\n" +
+	"\x14SignatureParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x87\x01\n" +
+	"\x19ListInstallEventsResponse\x12.\n" +
+	"\x06events\x18\x01 \x03(\v2\x16.protobuf.InstallEventR\x06events\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x19\n" +
+	"\bhas_more\x18\x03 \x01(\bR\ahasMore\"\xed\x01\n" +
+	"\x16GetInstallStatsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x1e\n" +
+	"\bapp_type\x18\x02 \x01(\rH\x00R\aappType\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\x03 \x01(\tR\tchannelId\x129\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendTimeB\v\n" +
+	"\t_app_type\"J\n" +
+	"\x13ChannelInstallStats\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\x01 \x01(\tR\tchannelId\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"F\n" +
+	"\x13AppTypeInstallStats\x12\x19\n" +
+	"\bapp_type\x18\x01 \x01(\rR\aappType\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xb1\x03\n" +
+	"\x17GetInstallStatsResponse\x12!\n" +
+	"\ftotal_events\x18\x01 \x01(\x03R\vtotalEvents\x12%\n" +
+	"\x0esuccess_events\x18\x02 \x01(\x03R\rsuccessEvents\x12#\n" +
+	"\rfailed_events\x18\x03 \x01(\x03R\ffailedEvents\x12!\n" +
+	"\fsuccess_rate\x18\x04 \x01(\x01R\vsuccessRate\x12%\n" +
+	"\x0efirst_installs\x18\x05 \x01(\x03R\rfirstInstalls\x12'\n" +
+	"\x0frepeat_installs\x18\x06 \x01(\x03R\x0erepeatInstalls\x12%\n" +
+	"\x0eunique_devices\x18\a \x01(\x03R\runiqueDevices\x12@\n" +
+	"\ftop_channels\x18\b \x03(\v2\x1d.protobuf.ChannelInstallStatsR\vtopChannels\x12K\n" +
+	"\x12app_type_breakdown\x18\t \x03(\v2\x1d.protobuf.AppTypeInstallStatsR\x10appTypeBreakdown\"\xcb\x02\n" +
+	"\x1bGetInstallTimeSeriesRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x1e\n" +
+	"\bapp_type\x18\x02 \x01(\rH\x00R\aappType\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\x03 \x01(\tR\tchannelId\x129\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12 \n" +
+	"\vgranularity\x18\x06 \x01(\tR\vgranularity\x12\x19\n" +
+	"\bgroup_by\x18\a \x01(\tR\agroupBy\x12\x1a\n" +
+	"\btimezone\x18\b \x01(\tR\btimezoneB\v\n" +
+	"\t_app_type\"\x97\x01\n" +
+	"\x12InstallMetricPoint\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\x03R\asuccess\x12!\n" +
+	"\fsuccess_rate\x18\x04 \x01(\x01R\vsuccessRate\"_\n" +
+	"\x11InstallTimeSeries\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x124\n" +
+	"\x06points\x18\x02 \x03(\v2\x1c.protobuf.InstallMetricPointR\x06points\"\xac\x01\n" +
+	"\x1cGetInstallTimeSeriesResponse\x12 \n" +
+	"\vgranularity\x18\x01 \x01(\tR\vgranularity\x12\x19\n" +
+	"\bgroup_by\x18\x02 \x01(\tR\agroupBy\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x123\n" +
+	"\x06series\x18\x04 \x03(\v2\x1b.protobuf.InstallTimeSeriesR\x06series2\x83\x04\n" +
 	"\x13InstallEventService\x12_\n" +
 	"\x12CreateInstallEvent\x12#.protobuf.CreateInstallEventRequest\x1a$.protobuf.CreateInstallEventResponse\x12n\n" +
-	"\x17CreateInstallEventBatch\x12(.protobuf.CreateInstallEventBatchRequest\x1a).protobuf.CreateInstallEventBatchResponseB<Z:github.com/iswangwenbin/gin-starter/internal/grpc/protobufb\x06proto3"
+	"\x17CreateInstallEventBatch\x12(.protobuf.CreateInstallEventBatchRequest\x1a).protobuf.CreateInstallEventBatchResponse\x12\\\n" +
+	"\x11ListInstallEvents\x12\".protobuf.ListInstallEventsRequest\x1a#.protobuf.ListInstallEventsResponse\x12V\n" +
+	"\x0fGetInstallStats\x12 .protobuf.GetInstallStatsRequest\x1a!.protobuf.GetInstallStatsResponse\x12e\n" +
+	"\x14GetInstallTimeSeries\x12%.protobuf.GetInstallTimeSeriesRequest\x1a&.protobuf.GetInstallTimeSeriesResponseB<Z:github.com/iswangwenbin/gin-starter/internal/grpc/protobufb\x06proto3"
 
 var (
 	file_install_event_proto_rawDescOnce sync.Once
@@ -440,28 +1519,60 @@ func file_install_event_proto_rawDescGZIP() []byte {
 	return file_install_event_proto_rawDescData
 }
 
-var file_install_event_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_install_event_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_install_event_proto_goTypes = []any{
 	(*CreateInstallEventRequest)(nil),       // 0: protobuf.CreateInstallEventRequest
 	(*CreateInstallEventResponse)(nil),      // 1: protobuf.CreateInstallEventResponse
 	(*CreateInstallEventBatchRequest)(nil),  // 2: protobuf.CreateInstallEventBatchRequest
 	(*CreateInstallEventBatchResponse)(nil), // 3: protobuf.CreateInstallEventBatchResponse
-	nil,                                     // 4: protobuf.CreateInstallEventRequest.SignatureParamsEntry
-	(*timestamppb.Timestamp)(nil),           // 5: google.protobuf.Timestamp
+	(*ListInstallEventsRequest)(nil),        // 4: protobuf.ListInstallEventsRequest
+	(*InstallEvent)(nil),                    // 5: protobuf.InstallEvent
+	(*ListInstallEventsResponse)(nil),       // 6: protobuf.ListInstallEventsResponse
+	(*GetInstallStatsRequest)(nil),          // 7: protobuf.GetInstallStatsRequest
+	(*ChannelInstallStats)(nil),             // 8: protobuf.ChannelInstallStats
+	(*AppTypeInstallStats)(nil),             // 9: protobuf.AppTypeInstallStats
+	(*GetInstallStatsResponse)(nil),         // 10: protobuf.GetInstallStatsResponse
+	(*GetInstallTimeSeriesRequest)(nil),     // 11: protobuf.GetInstallTimeSeriesRequest
+	(*InstallMetricPoint)(nil),              // 12: protobuf.InstallMetricPoint
+	(*InstallTimeSeries)(nil),               // 13: protobuf.InstallTimeSeries
+	(*GetInstallTimeSeriesResponse)(nil),    // 14: protobuf.GetInstallTimeSeriesResponse
+	nil,                                     // 15: protobuf.CreateInstallEventRequest.SignatureParamsEntry
+	nil,                                     // 16: protobuf.InstallEvent.SignatureParamsEntry
+	(*timestamppb.Timestamp)(nil),           // 17: google.protobuf.Timestamp
 }
 var file_install_event_proto_depIdxs = []int32{
-	5, // 0: protobuf.CreateInstallEventRequest.event_time:type_name -> google.protobuf.Timestamp
-	4, // 1: protobuf.CreateInstallEventRequest.signature_params:type_name -> protobuf.CreateInstallEventRequest.SignatureParamsEntry
-	0, // 2: protobuf.CreateInstallEventBatchRequest.events:type_name -> protobuf.CreateInstallEventRequest
-	0, // 3: protobuf.InstallEventService.CreateInstallEvent:input_type -> protobuf.CreateInstallEventRequest
-	2, // 4: protobuf.InstallEventService.CreateInstallEventBatch:input_type -> protobuf.CreateInstallEventBatchRequest
-	1, // 5: protobuf.InstallEventService.CreateInstallEvent:output_type -> protobuf.CreateInstallEventResponse
-	3, // 6: protobuf.InstallEventService.CreateInstallEventBatch:output_type -> protobuf.CreateInstallEventBatchResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	17, // 0: protobuf.CreateInstallEventRequest.event_time:type_name -> google.protobuf.Timestamp
+	15, // 1: protobuf.CreateInstallEventRequest.signature_params:type_name -> protobuf.CreateInstallEventRequest.SignatureParamsEntry
+	0,  // 2: protobuf.CreateInstallEventBatchRequest.events:type_name -> protobuf.CreateInstallEventRequest
+	17, // 3: protobuf.ListInstallEventsRequest.start_time:type_name -> google.protobuf.Timestamp
+	17, // 4: protobuf.ListInstallEventsRequest.end_time:type_name -> google.protobuf.Timestamp
+	17, // 5: protobuf.InstallEvent.event_time:type_name -> google.protobuf.Timestamp
+	16, // 6: protobuf.InstallEvent.signature_params:type_name -> protobuf.InstallEvent.SignatureParamsEntry
+	5,  // 7: protobuf.ListInstallEventsResponse.events:type_name -> protobuf.InstallEvent
+	17, // 8: protobuf.GetInstallStatsRequest.start_time:type_name -> google.protobuf.Timestamp
+	17, // 9: protobuf.GetInstallStatsRequest.end_time:type_name -> google.protobuf.Timestamp
+	8,  // 10: protobuf.GetInstallStatsResponse.top_channels:type_name -> protobuf.ChannelInstallStats
+	9,  // 11: protobuf.GetInstallStatsResponse.app_type_breakdown:type_name -> protobuf.AppTypeInstallStats
+	17, // 12: protobuf.GetInstallTimeSeriesRequest.start_time:type_name -> google.protobuf.Timestamp
+	17, // 13: protobuf.GetInstallTimeSeriesRequest.end_time:type_name -> google.protobuf.Timestamp
+	17, // 14: protobuf.InstallMetricPoint.time:type_name -> google.protobuf.Timestamp
+	12, // 15: protobuf.InstallTimeSeries.points:type_name -> protobuf.InstallMetricPoint
+	13, // 16: protobuf.GetInstallTimeSeriesResponse.series:type_name -> protobuf.InstallTimeSeries
+	0,  // 17: protobuf.InstallEventService.CreateInstallEvent:input_type -> protobuf.CreateInstallEventRequest
+	2,  // 18: protobuf.InstallEventService.CreateInstallEventBatch:input_type -> protobuf.CreateInstallEventBatchRequest
+	4,  // 19: protobuf.InstallEventService.ListInstallEvents:input_type -> protobuf.ListInstallEventsRequest
+	7,  // 20: protobuf.InstallEventService.GetInstallStats:input_type -> protobuf.GetInstallStatsRequest
+	11, // 21: protobuf.InstallEventService.GetInstallTimeSeries:input_type -> protobuf.GetInstallTimeSeriesRequest
+	1,  // 22: protobuf.InstallEventService.CreateInstallEvent:output_type -> protobuf.CreateInstallEventResponse
+	3,  // 23: protobuf.InstallEventService.CreateInstallEventBatch:output_type -> protobuf.CreateInstallEventBatchResponse
+	6,  // 24: protobuf.InstallEventService.ListInstallEvents:output_type -> protobuf.ListInstallEventsResponse
+	10, // 25: protobuf.InstallEventService.GetInstallStats:output_type -> protobuf.GetInstallStatsResponse
+	14, // 26: protobuf.InstallEventService.GetInstallTimeSeries:output_type -> protobuf.GetInstallTimeSeriesResponse
+	22, // [22:27] is the sub-list for method output_type
+	17, // [17:22] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_install_event_proto_init() }
@@ -469,13 +1580,16 @@ func file_install_event_proto_init() {
 	if File_install_event_proto != nil {
 		return
 	}
+	file_install_event_proto_msgTypes[4].OneofWrappers = []any{}
+	file_install_event_proto_msgTypes[7].OneofWrappers = []any{}
+	file_install_event_proto_msgTypes[11].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_install_event_proto_rawDesc), len(file_install_event_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,6 +21,9 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	InstallEventService_CreateInstallEvent_FullMethodName      = "/protobuf.InstallEventService/CreateInstallEvent"
 	InstallEventService_CreateInstallEventBatch_FullMethodName = "/protobuf.InstallEventService/CreateInstallEventBatch"
+	InstallEventService_ListInstallEvents_FullMethodName       = "/protobuf.InstallEventService/ListInstallEvents"
+	InstallEventService_GetInstallStats_FullMethodName         = "/protobuf.InstallEventService/GetInstallStats"
+	InstallEventService_GetInstallTimeSeries_FullMethodName    = "/protobuf.InstallEventService/GetInstallTimeSeries"
 )
 
 // InstallEventServiceClient is the client API for InstallEventService service.
@@ -33,6 +36,12 @@ type InstallEventServiceClient interface {
 	CreateInstallEvent(ctx context.Context, in *CreateInstallEventRequest, opts ...grpc.CallOption) (*CreateInstallEventResponse, error)
 	// 批量创建安装事件
 	CreateInstallEventBatch(ctx context.Context, in *CreateInstallEventBatchRequest, opts ...grpc.CallOption) (*CreateInstallEventBatchResponse, error)
+	// 按时间倒序游标分页查询安装事件（需要 JWT 及 events:read 权限）
+	ListInstallEvents(ctx context.Context, in *ListInstallEventsRequest, opts ...grpc.CallOption) (*ListInstallEventsResponse, error)
+	// 统计时间窗口内的安装数据（需要 JWT 及 events:read 权限）
+	GetInstallStats(ctx context.Context, in *GetInstallStatsRequest, opts ...grpc.CallOption) (*GetInstallStatsResponse, error)
+	// 按粒度、分组维度和时区查询安装趋势（需要 JWT 及 events:read 权限）
+	GetInstallTimeSeries(ctx context.Context, in *GetInstallTimeSeriesRequest, opts ...grpc.CallOption) (*GetInstallTimeSeriesResponse, error)
 }
 
 type installEventServiceClient struct {
@@ -63,6 +72,36 @@ func (c *installEventServiceClient) CreateInstallEventBatch(ctx context.Context,
 	return out, nil
 }
 
+func (c *installEventServiceClient) ListInstallEvents(ctx context.Context, in *ListInstallEventsRequest, opts ...grpc.CallOption) (*ListInstallEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstallEventsResponse)
+	err := c.cc.Invoke(ctx, InstallEventService_ListInstallEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *installEventServiceClient) GetInstallStats(ctx context.Context, in *GetInstallStatsRequest, opts ...grpc.CallOption) (*GetInstallStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstallStatsResponse)
+	err := c.cc.Invoke(ctx, InstallEventService_GetInstallStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *installEventServiceClient) GetInstallTimeSeries(ctx context.Context, in *GetInstallTimeSeriesRequest, opts ...grpc.CallOption) (*GetInstallTimeSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstallTimeSeriesResponse)
+	err := c.cc.Invoke(ctx, InstallEventService_GetInstallTimeSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InstallEventServiceServer is the server API for InstallEventService service.
 // All implementations must embed UnimplementedInstallEventServiceServer
 // for forward compatibility.
@@ -73,6 +112,12 @@ type InstallEventServiceServer interface {
 	CreateInstallEvent(context.Context, *CreateInstallEventRequest) (*CreateInstallEventResponse, error)
 	// 批量创建安装事件
 	CreateInstallEventBatch(context.Context, *CreateInstallEventBatchRequest) (*CreateInstallEventBatchResponse, error)
+	// 按时间倒序游标分页查询安装事件（需要 JWT 及 events:read 权限）
+	ListInstallEvents(context.Context, *ListInstallEventsRequest) (*ListInstallEventsResponse, error)
+	// 统计时间窗口内的安装数据（需要 JWT 及 events:read 权限）
+	GetInstallStats(context.Context, *GetInstallStatsRequest) (*GetInstallStatsResponse, error)
+	// 按粒度、分组维度和时区查询安装趋势（需要 JWT 及 events:read 权限）
+	GetInstallTimeSeries(context.Context, *GetInstallTimeSeriesRequest) (*GetInstallTimeSeriesResponse, error)
 	mustEmbedUnimplementedInstallEventServiceServer()
 }
 
@@ -89,6 +134,15 @@ func (UnimplementedInstallEventServiceServer) CreateInstallEvent(context.Context
 func (UnimplementedInstallEventServiceServer) CreateInstallEventBatch(context.Context, *CreateInstallEventBatchRequest) (*CreateInstallEventBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateInstallEventBatch not implemented")
 }
+func (UnimplementedInstallEventServiceServer) ListInstallEvents(context.Context, *ListInstallEventsRequest) (*ListInstallEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstallEvents not implemented")
+}
+func (UnimplementedInstallEventServiceServer) GetInstallStats(context.Context, *GetInstallStatsRequest) (*GetInstallStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstallStats not implemented")
+}
+func (UnimplementedInstallEventServiceServer) GetInstallTimeSeries(context.Context, *GetInstallTimeSeriesRequest) (*GetInstallTimeSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstallTimeSeries not implemented")
+}
 func (UnimplementedInstallEventServiceServer) mustEmbedUnimplementedInstallEventServiceServer() {}
 func (UnimplementedInstallEventServiceServer) testEmbeddedByValue()                             {}
 
@@ -146,6 +200,60 @@ func _InstallEventService_CreateInstallEventBatch_Handler(srv interface{}, ctx c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InstallEventService_ListInstallEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstallEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstallEventServiceServer).ListInstallEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstallEventService_ListInstallEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstallEventServiceServer).ListInstallEvents(ctx, req.(*ListInstallEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstallEventService_GetInstallStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstallStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstallEventServiceServer).GetInstallStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstallEventService_GetInstallStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstallEventServiceServer).GetInstallStats(ctx, req.(*GetInstallStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstallEventService_GetInstallTimeSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstallTimeSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstallEventServiceServer).GetInstallTimeSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstallEventService_GetInstallTimeSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstallEventServiceServer).GetInstallTimeSeries(ctx, req.(*GetInstallTimeSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InstallEventService_ServiceDesc is the grpc.ServiceDesc for InstallEventService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +269,18 @@ var InstallEventService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateInstallEventBatch",
 			Handler:    _InstallEventService_CreateInstallEventBatch_Handler,
 		},
+		{
+			MethodName: "ListInstallEvents",
+			Handler:    _InstallEventService_ListInstallEvents_Handler,
+		},
+		{
+			MethodName: "GetInstallStats",
+			Handler:    _InstallEventService_GetInstallStats_Handler,
+		},
+		{
+			MethodName: "GetInstallTimeSeries",
+			Handler:    _InstallEventService_GetInstallTimeSeries_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "install_event.proto",
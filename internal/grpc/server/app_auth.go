@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/iswangwenbin/gin-starter/pkg/signx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authenticateApp 校验 gRPC 上报请求的签名：签名覆盖方法名、时间戳、nonce 和客户端在 metadata 中声明的载荷摘要，
+// 声明的摘要必须与服务端收到的原始字节一致；签名认证关闭时仍要求请求中的 app_id 已注册且处于启用状态
+func authenticateApp(ctx context.Context, appService *service.AppService, appIDs []string) error {
+	if cfg := configx.GetConfig(); cfg != nil && !cfg.AppAuth.Enabled {
+		for _, appID := range appIDs {
+			if err := appService.ValidateApp(ctx, appID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	timestamp, _ := strconv.ParseInt(firstMetadata(md, signx.HeaderTimestamp), 10, 64)
+	sig := &model.AppSignature{
+		AppID:     firstMetadata(md, signx.HeaderAppID),
+		KeyID:     firstMetadata(md, signx.HeaderKeyID),
+		Timestamp: timestamp,
+		Signature: firstMetadata(md, signx.HeaderSignature),
+	}
+	method, _ := grpc.Method(ctx)
+	claimed := firstMetadata(md, signx.HeaderContentSHA256)
+
+	// 先比对载荷摘要，不一致的请求不消耗防重放记录
+	if claimed != "" {
+		received := payloadSHA256FromContext(ctx)
+		if received == "" {
+			return errorsx.New(errorsx.CodeInternalServerError, "Request payload digest unavailable")
+		}
+		if !hmac.Equal([]byte(received), []byte(strings.ToLower(claimed))) {
+			return errorsx.New(errorsx.CodeSignatureInvalid, "Payload digest does not match request")
+		}
+	}
+
+	app, err := appService.AuthenticateGRPC(ctx, sig, method, firstMetadata(md, signx.HeaderNonce), claimed)
+	if err != nil {
+		return err
+	}
+
+	// 一个应用的密钥只能上报本应用的事件
+	for _, appID := range appIDs {
+		if appID != app.AppID {
+			return errorsx.New(errorsx.CodeForbidden, fmt.Sprintf("Event app_id %q does not match authenticated app", appID))
+		}
+	}
+	return nil
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
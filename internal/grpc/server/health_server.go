@@ -2,27 +2,177 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"gorm.io/gorm"
 )
 
-// HealthServer 健康检查服务的 gRPC 服务端实现
+// 可单独检查的依赖名称，空字符串表示整体状态
+const (
+	HealthServiceMySQL      = "mysql"
+	HealthServiceRedis      = "redis"
+	HealthServiceClickHouse = "clickhouse"
+)
+
+// healthCheckTimeout 单个依赖的探测超时
+const healthCheckTimeout = 2 * time.Second
+
+// HealthServer 健康检查服务的 gRPC 服务端实现，同时维护标准 grpc.health.v1 的状态
 type HealthServer struct {
 	protobuf.UnimplementedHealthServiceServer
+	db       *gorm.DB
+	cache    *redis.Client
+	ch       clickhouse.Conn
+	logger   *zap.Logger
+	standard *health.Server
 }
 
-// NewHealthServer 创建健康检查服务的 gRPC 服务端
-func NewHealthServer() *HealthServer {
-	return &HealthServer{}
+// NewHealthServer 创建健康检查服务的 gRPC 服务端，未初始化的依赖不参与检查
+func NewHealthServer(db *gorm.DB, cache *redis.Client, ch clickhouse.Conn, logger *zap.Logger) *HealthServer {
+	return &HealthServer{
+		db:       db,
+		cache:    cache,
+		ch:       ch,
+		logger:   logger,
+		standard: health.NewServer(),
+	}
 }
 
-// Check 健康检查
+// Check 健康检查，service 为空时检查全部依赖
 func (s *HealthServer) Check(ctx context.Context, req *protobuf.HealthCheckRequest) (*protobuf.HealthCheckResponse, error) {
-	// 这里可以添加实际的健康检查逻辑
-	// 例如：检查数据库连接、Redis连接等
-	
+	checks := s.checks()
+
+	if req.Service != "" {
+		check, ok := checks[req.Service]
+		if !ok {
+			return nil, errorsx.New(errorsx.CodeNotFound, fmt.Sprintf("Unknown service %q", req.Service))
+		}
+		if err := probe(ctx, check); err != nil {
+			return &protobuf.HealthCheckResponse{
+				Status:  protobuf.HealthCheckResponse_NOT_SERVING,
+				Message: fmt.Sprintf("%s: %v", req.Service, err),
+			}, nil
+		}
+		return &protobuf.HealthCheckResponse{
+			Status:  protobuf.HealthCheckResponse_SERVING,
+			Message: req.Service + " is healthy",
+		}, nil
+	}
+
+	failures := s.checkAll(ctx, checks)
+	if len(failures) > 0 {
+		return &protobuf.HealthCheckResponse{
+			Status:  protobuf.HealthCheckResponse_NOT_SERVING,
+			Message: strings.Join(failures, "; "),
+		}, nil
+	}
+
 	return &protobuf.HealthCheckResponse{
 		Status:  protobuf.HealthCheckResponse_SERVING,
 		Message: "Service is healthy",
 	}, nil
-}
\ No newline at end of file
+}
+
+// Standard 标准 grpc.health.v1 服务实现
+func (s *HealthServer) Standard() *health.Server {
+	return s.standard
+}
+
+// Watch 周期性探测依赖并更新 grpc.health.v1 的状态，ctx 取消后将全部状态置为 NOT_SERVING
+func (s *HealthServer) Watch(ctx context.Context, interval time.Duration, services []string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.refresh(ctx, services)
+
+		select {
+		case <-ctx.Done():
+			s.standard.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh 更新各依赖及已注册 gRPC 服务的状态，gRPC 服务状态跟随整体状态
+func (s *HealthServer) refresh(ctx context.Context, services []string) {
+	checks := s.checks()
+	overall := grpc_health_v1.HealthCheckResponse_SERVING
+
+	for name, check := range checks {
+		serving := grpc_health_v1.HealthCheckResponse_SERVING
+		if err := probe(ctx, check); err != nil {
+			serving = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			overall = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			s.logger.Warn("health check failed", zap.String("service", name), zap.Error(err))
+		}
+		s.standard.SetServingStatus(name, serving)
+	}
+
+	s.standard.SetServingStatus("", overall)
+	for _, name := range services {
+		s.standard.SetServingStatus(name, overall)
+	}
+}
+
+func (s *HealthServer) checkAll(ctx context.Context, checks map[string]func(context.Context) error) []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		if err := probe(ctx, checks[name]); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return failures
+}
+
+// checks 已初始化依赖的探测函数
+func (s *HealthServer) checks() map[string]func(context.Context) error {
+	checks := make(map[string]func(context.Context) error)
+
+	if s.db != nil {
+		checks[HealthServiceMySQL] = func(ctx context.Context) error {
+			sqlDB, err := s.db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}
+	}
+
+	if s.cache != nil {
+		checks[HealthServiceRedis] = func(ctx context.Context) error {
+			return s.cache.Ping(ctx).Err()
+		}
+	}
+
+	if s.ch != nil {
+		checks[HealthServiceClickHouse] = func(ctx context.Context) error {
+			return s.ch.Ping(ctx)
+		}
+	}
+
+	return checks
+}
+
+func probe(ctx context.Context, check func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return check(ctx)
+}
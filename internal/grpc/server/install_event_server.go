@@ -6,26 +6,41 @@ import (
 	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
 	"github.com/iswangwenbin/gin-starter/internal/model"
 	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type InstallEventServer struct {
 	protobuf.UnimplementedInstallEventServiceServer
 	installEventService *service.InstallEventService
+	queryService        *service.InstallEventQueryService
+	appService          *service.AppService
+	rbacService         *service.RBACService
 	logger              *zap.Logger
 }
 
-func NewInstallEventServer(installEventService *service.InstallEventService, logger *zap.Logger) *InstallEventServer {
+func NewInstallEventServer(installEventService *service.InstallEventService, queryService *service.InstallEventQueryService, appService *service.AppService, rbacService *service.RBACService, logger *zap.Logger) *InstallEventServer {
 	return &InstallEventServer{
 		installEventService: installEventService,
+		queryService:        queryService,
+		appService:          appService,
+		rbacService:         rbacService,
 		logger:              logger,
 	}
 }
 
 // 创建单个安装事件
 func (s *InstallEventServer) CreateInstallEvent(ctx context.Context, req *protobuf.CreateInstallEventRequest) (*protobuf.CreateInstallEventResponse, error) {
+	// 校验应用签名，未通过时不写入 Stream
+	if err := authenticateApp(ctx, s.appService, []string{req.AppId}); err != nil {
+		s.logger.Warn("Install event rejected",
+			zap.String("app_id", req.AppId),
+			zap.String("event_id", req.EventId),
+			zap.Error(err))
+		return nil, err
+	}
+
 	// 转换 protobuf 请求到内部模型
 	createReq := &model.CreateInstallEventRequest{
 		AppID:            req.AppId,
@@ -55,7 +70,7 @@ func (s *InstallEventServer) CreateInstallEvent(ctx context.Context, req *protob
 		s.logger.Error("Failed to create install event via gRPC",
 			zap.String("event_id", req.EventId),
 			zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "Failed to create install event: %v", err)
+		return nil, err
 	}
 
 	return &protobuf.CreateInstallEventResponse{
@@ -67,7 +82,24 @@ func (s *InstallEventServer) CreateInstallEvent(ctx context.Context, req *protob
 // 批量创建安装事件
 func (s *InstallEventServer) CreateInstallEventBatch(ctx context.Context, req *protobuf.CreateInstallEventBatchRequest) (*protobuf.CreateInstallEventBatchResponse, error) {
 	if len(req.Events) == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "No events provided")
+		return nil, errorsx.New(errorsx.CodeBadRequest, "No events provided")
+	}
+
+	// 校验应用签名，批量中的事件必须全部属于认证的应用
+	appIDs := make([]string, 0, 1)
+	seen := make(map[string]bool)
+	for _, event := range req.Events {
+		if !seen[event.AppId] {
+			seen[event.AppId] = true
+			appIDs = append(appIDs, event.AppId)
+		}
+	}
+	if err := authenticateApp(ctx, s.appService, appIDs); err != nil {
+		s.logger.Warn("Install events batch rejected",
+			zap.Strings("app_ids", appIDs),
+			zap.Int("count", len(req.Events)),
+			zap.Error(err))
+		return nil, err
 	}
 
 	// 转换 protobuf 请求到内部模型
@@ -99,16 +131,201 @@ func (s *InstallEventServer) CreateInstallEventBatch(ctx context.Context, req *p
 	}
 
 	// 调用服务层批量创建
-	if err := s.installEventService.CreateBatch(ctx, createReqs); err != nil {
+	result, err := s.installEventService.CreateBatch(ctx, createReqs)
+	if err != nil {
 		s.logger.Error("Failed to create install events batch via gRPC",
 			zap.Int("count", len(req.Events)),
 			zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "Failed to create install events batch: %v", err)
+		return nil, err
 	}
 
 	return &protobuf.CreateInstallEventBatchResponse{
-		Success:     true,
-		Message:     "Install events batch created successfully",
-		ProcessedCount: int32(len(req.Events)),
+		Success:           true,
+		Message:           "Install events batch created successfully",
+		ProcessedCount:    int32(result.Accepted),
+		DuplicateEventIds: result.Duplicates,
 	}, nil
-}
\ No newline at end of file
+}
+
+// 按时间倒序游标分页查询安装事件
+func (s *InstallEventServer) ListInstallEvents(ctx context.Context, req *protobuf.ListInstallEventsRequest) (*protobuf.ListInstallEventsResponse, error) {
+	if err := requirePermission(ctx, s.rbacService, model.PermissionEventsRead); err != nil {
+		return nil, err
+	}
+
+	listReq := &model.InstallEventListRequest{
+		AppID:     req.AppId,
+		DeviceID:  req.DeviceId,
+		ChannelID: req.ChannelId,
+		Size:      int(req.PageSize),
+		Cursor:    req.Cursor,
+	}
+	if req.AppType != nil {
+		appType := model.AppType(*req.AppType)
+		listReq.AppType = &appType
+	}
+	if req.InstallType != nil {
+		installType := model.InstallType(*req.InstallType)
+		listReq.InstallType = &installType
+	}
+	if req.InstallResult != nil {
+		installResult := model.InstallResult(*req.InstallResult)
+		listReq.InstallResult = &installResult
+	}
+	if req.StartTime != nil {
+		startTime := req.StartTime.AsTime()
+		listReq.StartTime = &startTime
+	}
+	if req.EndTime != nil {
+		endTime := req.EndTime.AsTime()
+		listReq.EndTime = &endTime
+	}
+
+	result, err := s.queryService.List(ctx, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &protobuf.ListInstallEventsResponse{
+		Events:     make([]*protobuf.InstallEvent, 0, len(result.Events)),
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}
+	for _, event := range result.Events {
+		resp.Events = append(resp.Events, &protobuf.InstallEvent{
+			AppId:            event.AppID,
+			AppName:          event.AppName,
+			AppVersion:       event.AppVersion,
+			AppType:          uint32(event.AppType),
+			EventId:          event.EventID,
+			EventTime:        timestamppb.New(event.EventTime),
+			DeviceId:         event.DeviceID,
+			ChannelId:        event.ChannelID,
+			InstallIp:        event.InstallIP,
+			InstallType:      uint32(event.InstallType),
+			InstallResult:    uint32(event.InstallResult),
+			OsLanguage:       event.OSLanguage,
+			OsTimezone:       event.OSTimezone,
+			OsName:           event.OSName,
+			OsVersion:        event.OSVersion,
+			OsBuild:          event.OSBuild,
+			OsFamily:         event.OSFamily,
+			SignatureStatus:  uint32(event.SignatureStatus),
+			SignatureVersion: event.SignatureVersion,
+			SignatureParams:  event.SignatureParams,
+		})
+	}
+
+	return resp, nil
+}
+
+// 统计时间窗口内的安装数据
+func (s *InstallEventServer) GetInstallStats(ctx context.Context, req *protobuf.GetInstallStatsRequest) (*protobuf.GetInstallStatsResponse, error) {
+	if err := requirePermission(ctx, s.rbacService, model.PermissionEventsRead); err != nil {
+		return nil, err
+	}
+
+	statsReq := &model.InstallStatsRequest{
+		AppID:     req.AppId,
+		ChannelID: req.ChannelId,
+	}
+	if req.AppType != nil {
+		appType := model.AppType(*req.AppType)
+		statsReq.AppType = &appType
+	}
+	if req.StartTime != nil {
+		startTime := req.StartTime.AsTime()
+		statsReq.StartTime = &startTime
+	}
+	if req.EndTime != nil {
+		endTime := req.EndTime.AsTime()
+		statsReq.EndTime = &endTime
+	}
+
+	stats, err := s.queryService.Stats(ctx, statsReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &protobuf.GetInstallStatsResponse{
+		TotalEvents:      stats.TotalEvents,
+		SuccessEvents:    stats.SuccessEvents,
+		FailedEvents:     stats.FailedEvents,
+		SuccessRate:      stats.SuccessRate,
+		FirstInstalls:    stats.FirstInstalls,
+		RepeatInstalls:   stats.RepeatInstalls,
+		UniqueDevices:    stats.UniqueDevices,
+		TopChannels:      make([]*protobuf.ChannelInstallStats, 0, len(stats.TopChannels)),
+		AppTypeBreakdown: make([]*protobuf.AppTypeInstallStats, 0, len(stats.AppTypeBreakdown)),
+	}
+	for _, channel := range stats.TopChannels {
+		resp.TopChannels = append(resp.TopChannels, &protobuf.ChannelInstallStats{
+			ChannelId: channel.ChannelID,
+			Count:     channel.Count,
+		})
+	}
+	for _, appType := range stats.AppTypeBreakdown {
+		resp.AppTypeBreakdown = append(resp.AppTypeBreakdown, &protobuf.AppTypeInstallStats{
+			AppType: uint32(appType.AppType),
+			Count:   appType.Count,
+		})
+	}
+
+	return resp, nil
+}
+
+// 按粒度、分组维度和时区查询安装趋势
+func (s *InstallEventServer) GetInstallTimeSeries(ctx context.Context, req *protobuf.GetInstallTimeSeriesRequest) (*protobuf.GetInstallTimeSeriesResponse, error) {
+	if err := requirePermission(ctx, s.rbacService, model.PermissionEventsRead); err != nil {
+		return nil, err
+	}
+
+	seriesReq := &model.InstallTimeSeriesRequest{
+		AppID:       req.AppId,
+		ChannelID:   req.ChannelId,
+		Granularity: model.TimeGranularity(req.Granularity),
+		GroupBy:     req.GroupBy,
+		Timezone:    req.Timezone,
+	}
+	if req.AppType != nil {
+		appType := model.AppType(*req.AppType)
+		seriesReq.AppType = &appType
+	}
+	if req.StartTime != nil {
+		startTime := req.StartTime.AsTime()
+		seriesReq.StartTime = &startTime
+	}
+	if req.EndTime != nil {
+		endTime := req.EndTime.AsTime()
+		seriesReq.EndTime = &endTime
+	}
+
+	result, err := s.queryService.TimeSeries(ctx, seriesReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &protobuf.GetInstallTimeSeriesResponse{
+		Granularity: string(result.Granularity),
+		GroupBy:     result.GroupBy,
+		Timezone:    result.Timezone,
+		Series:      make([]*protobuf.InstallTimeSeries, 0, len(result.Series)),
+	}
+	for _, series := range result.Series {
+		pbSeries := &protobuf.InstallTimeSeries{
+			Group:  series.Group,
+			Points: make([]*protobuf.InstallMetricPoint, 0, len(series.Points)),
+		}
+		for _, point := range series.Points {
+			pbSeries.Points = append(pbSeries.Points, &protobuf.InstallMetricPoint{
+				Time:        timestamppb.New(point.Time),
+				Total:       point.Total,
+				Success:     point.Success,
+				SuccessRate: point.SuccessRate,
+			})
+		}
+		resp.Series = append(resp.Series, pbSeries)
+	}
+
+	return resp, nil
+}
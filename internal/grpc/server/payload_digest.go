@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/grpc/stats"
+)
+
+// pendingDigests 编解码器解出的请求消息到其原始字节摘要的映射，
+// 统计处理器在同一次解码后立即取出，不会长期保留
+var pendingDigests sync.Map
+
+// payloadDigestCodec 在 protobuf 解码时记录收到的原始字节（已解压）的 SHA256，
+// 签名认证据此校验客户端声明的载荷摘要，而不是依赖服务端重新编码
+type payloadDigestCodec struct {
+	encoding.CodecV2
+}
+
+func newPayloadDigestCodec() encoding.CodecV2 {
+	return payloadDigestCodec{CodecV2: encoding.GetCodecV2(proto.Name)}
+}
+
+// Unmarshal 先计算摘要再解码，解码后 data 可能被释放
+func (c payloadDigestCodec) Unmarshal(data mem.BufferSlice, v any) error {
+	h := sha256.New()
+	for _, buf := range data {
+		h.Write(buf.ReadOnlyData())
+	}
+	if err := c.CodecV2.Unmarshal(data, v); err != nil {
+		return err
+	}
+	pendingDigests.Store(v, hex.EncodeToString(h.Sum(nil)))
+	return nil
+}
+
+type payloadDigestKey struct{}
+
+// payloadDigest 请求上下文中保存的载荷摘要，解码和处理在同一个 goroutine 中进行
+type payloadDigest struct {
+	sha256 string
+}
+
+// payloadDigestHandler 将编解码器记录的摘要移入请求上下文
+type payloadDigestHandler struct{}
+
+func (payloadDigestHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, payloadDigestKey{}, &payloadDigest{})
+}
+
+func (payloadDigestHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	in, ok := s.(*stats.InPayload)
+	if !ok || in.Client {
+		return
+	}
+	sum, ok := pendingDigests.LoadAndDelete(in.Payload)
+	if !ok {
+		return
+	}
+	if digest, ok := ctx.Value(payloadDigestKey{}).(*payloadDigest); ok {
+		digest.sha256 = sum.(string)
+	}
+}
+
+func (payloadDigestHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (payloadDigestHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// payloadSHA256FromContext 最近一次收到的请求消息原始字节的十六进制 SHA256
+func payloadSHA256FromContext(ctx context.Context) string {
+	if digest, ok := ctx.Value(payloadDigestKey{}).(*payloadDigest); ok {
+		return digest.sha256
+	}
+	return ""
+}
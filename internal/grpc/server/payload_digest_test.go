@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/iswangwenbin/gin-starter/pkg/signx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// 服务端记录的摘要应与客户端实际发送的字节一致
+func TestPayloadDigestMatchesSentBytes(t *testing.T) {
+	digests := make(chan string, 1)
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.ForceServerCodecV2(newPayloadDigestCodec()),
+		grpc.StatsHandler(payloadDigestHandler{}),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			digests <- payloadSHA256FromContext(ctx)
+			return handler(ctx, req)
+		}),
+	)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("svc", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(srv, healthServer)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	tests := []struct {
+		name string
+		req  *grpc_health_v1.HealthCheckRequest
+	}{
+		{name: "service", req: &grpc_health_v1.HealthCheckRequest{Service: "svc"}},
+		{name: "empty message", req: &grpc_health_v1.HealthCheckRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent, err := proto.Marshal(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			grpc_health_v1.NewHealthClient(conn).Check(context.Background(), tt.req)
+
+			if got, want := <-digests, signx.PayloadSHA256(sent); got != want {
+				t.Errorf("payload digest = %s, want %s", got, want)
+			}
+		})
+	}
+
+	pendingDigests.Range(func(key, _ any) bool {
+		t.Errorf("digest of %v was not consumed", key)
+		return true
+	})
+}
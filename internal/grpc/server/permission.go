@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+
+	"github.com/iswangwenbin/gin-starter/internal/grpc/interceptor"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+)
+
+// requirePermission 要求当前 JWT 用户拥有指定权限，与 HTTP 的 middleware.RequirePermission 一致
+func requirePermission(ctx context.Context, checker middleware.PermissionChecker, permission string) error {
+	claims := interceptor.ClaimsFromContext(ctx)
+	if claims == nil {
+		return errorsx.New(errorsx.CodeUnauthorized, "unauthorized")
+	}
+
+	allowed, err := checker.HasPermission(ctx, claims.Roles, permission)
+	if err != nil {
+		return errorsx.NewWithError(errorsx.CodeInternalServerError, "permission check failed", err)
+	}
+	if !allowed {
+		return errorsx.New(errorsx.ErrInsufficientPermission.Code, errorsx.ErrInsufficientPermission.Message,
+			map[string]string{"required_permission": permission})
+	}
+	return nil
+}
@@ -7,33 +7,57 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/iswangwenbin/gin-starter/internal/grpc/interceptor"
 	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
 	"github.com/iswangwenbin/gin-starter/internal/service"
 	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/tlsx"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// publicMethods 无需 JWT 认证的方法
+var publicMethods = interceptor.Allowlist{
+	protobuf.UserService_Login_FullMethodName,
+	protobuf.UserService_CreateUser_FullMethodName,
+	protobuf.HealthService_Check_FullMethodName,
+	"/grpc.health.v1.Health/",
+	// 事件上报使用应用 HMAC 签名认证
+	protobuf.InstallEventService_CreateInstallEvent_FullMethodName,
+	protobuf.InstallEventService_CreateInstallEventBatch_FullMethodName,
+}
+
+// healthWatchInterval grpc.health.v1 状态刷新间隔
+const healthWatchInterval = 10 * time.Second
+
 // Server gRPC 服务器
 type Server struct {
-	grpcServer *grpc.Server
-	config     *configx.Config
-	logger     *zap.Logger
-	db         *gorm.DB
-	cache      *redis.Client
+	grpcServer   *grpc.Server
+	healthServer *HealthServer
+	config       *configx.Config
+	logger       *zap.Logger
+	db           *gorm.DB
+	cache        *redis.Client
+	ch           clickhouse.Conn
 }
 
 // NewServer 创建 gRPC 服务器
-func NewServer(config *configx.Config, logger *zap.Logger, db *gorm.DB, cache *redis.Client) *Server {
+func NewServer(config *configx.Config, logger *zap.Logger, db *gorm.DB, cache *redis.Client, ch clickhouse.Conn) *Server {
 	return &Server{
 		config: config,
 		logger: logger,
 		db:     db,
 		cache:  cache,
+		ch:     ch,
 	}
 }
 
@@ -53,6 +77,25 @@ func (s *Server) Start(ctx context.Context) error {
 			PermitWithoutStream: true,
 		}),
 	}
+	opts = append(opts, s.interceptors()...)
+
+	// 记录请求消息原始字节的摘要，用于校验上报签名中的载荷摘要
+	opts = append(opts, grpc.ForceServerCodecV2(newPayloadDigestCodec()), grpc.StatsHandler(payloadDigestHandler{}))
+
+	// TLS 证书变化时自动重新加载
+	if s.config.GRPC.TLS.Enabled {
+		reloader, err := tlsx.NewReloader(s.config.GRPC.TLS, s.logger)
+		if err != nil {
+			return fmt.Errorf("failed to load gRPC TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.TLSConfig("h2"))))
+
+		go func() {
+			if err := reloader.Watch(ctx); err != nil {
+				s.logger.Error("gRPC TLS certificate watcher stopped", zap.Error(err))
+			}
+		}()
+	}
 
 	// 创建 gRPC 服务器
 	s.grpcServer = grpc.NewServer(opts...)
@@ -60,6 +103,9 @@ func (s *Server) Start(ctx context.Context) error {
 	// 注册服务
 	s.registerServices()
 
+	// 持续刷新标准健康检查状态
+	go s.healthServer.Watch(ctx, healthWatchInterval, s.serviceNames())
+
 	// 启用反射（开发环境）
 	if s.config.Debug {
 		reflection.Register(s.grpcServer)
@@ -72,7 +118,9 @@ func (s *Server) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
-	s.logger.Info("gRPC server starting", zap.String("address", addr))
+	s.logger.Info("gRPC server starting",
+		zap.String("address", addr),
+		zap.Bool("tls", s.config.GRPC.TLS.Enabled))
 
 	// 启动服务器
 	go func() {
@@ -98,14 +146,61 @@ func (s *Server) Stop() {
 	}
 }
 
+// interceptors 构建拦截器链：请求 ID -> 访问日志 -> 错误转换 -> panic 恢复 -> JWT 认证
+func (s *Server) interceptors() []grpc.ServerOption {
+	denylist := middleware.NewTokenDenylist(s.cache)
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			interceptor.RequestIDUnary(),
+			interceptor.LoggingUnary(s.logger),
+			interceptor.ErrorUnary(),
+			interceptor.RecoveryUnary(s.logger),
+			interceptor.AuthUnary(denylist, publicMethods, s.logger),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.RequestIDStream(),
+			interceptor.LoggingStream(s.logger),
+			interceptor.ErrorStream(),
+			interceptor.RecoveryStream(s.logger),
+			interceptor.AuthStream(denylist, publicMethods, s.logger),
+		),
+	}
+}
+
 // registerServices 注册 gRPC 服务
 func (s *Server) registerServices() {
+	baseService := service.NewBaseService(repository.NewRepositoryWithClickHouse(s.db, s.ch), s.cache, s.logger)
+	rbacService := service.NewRBACService(baseService)
+
 	// 创建安装事件服务
 	installEventService := service.NewInstallEventService(s.cache, s.logger)
-	installEventServer := NewInstallEventServer(installEventService, s.logger)
+	queryService := service.NewInstallEventQueryService(baseService)
+	appService := service.NewAppService(baseService)
+	installEventServer := NewInstallEventServer(installEventService, queryService, appService, rbacService, s.logger)
+
+	// 创建用户服务
+	userServer := NewUserServer(service.NewUserService(baseService), rbacService)
+
+	// 创建健康检查服务
+	s.healthServer = NewHealthServer(s.db, s.cache, s.ch, s.logger)
 
 	// 注册服务
 	protobuf.RegisterInstallEventServiceServer(s.grpcServer, installEventServer)
+	protobuf.RegisterUserServiceServer(s.grpcServer, userServer)
+	protobuf.RegisterHealthServiceServer(s.grpcServer, s.healthServer)
+	grpc_health_v1.RegisterHealthServer(s.grpcServer, s.healthServer.Standard())
 
 	s.logger.Info("gRPC services registered")
 }
+
+// serviceNames 已注册的业务服务名称，用于 grpc.health.v1 按服务查询
+func (s *Server) serviceNames() []string {
+	var names []string
+	for name := range s.grpcServer.GetServiceInfo() {
+		if name != grpc_health_v1.Health_ServiceDesc.ServiceName {
+			names = append(names, name)
+		}
+	}
+	return names
+}
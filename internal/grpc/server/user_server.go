@@ -3,9 +3,7 @@ package server
 import (
 	"context"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
+	"github.com/iswangwenbin/gin-starter/internal/grpc/interceptor"
 	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
 	"github.com/iswangwenbin/gin-starter/internal/middleware"
 	"github.com/iswangwenbin/gin-starter/internal/model"
@@ -13,16 +11,20 @@ import (
 	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
 )
 
-// UserServer 用户服务的 gRPC 服务端实现
+// UserServer 用户服务的 gRPC 服务端实现，用户管理方法与 HTTP 接口要求相同的 RBAC 权限
 type UserServer struct {
 	protobuf.UnimplementedUserServiceServer
 	userService *service.UserService
+	rbacService *service.RBACService
+	permissions middleware.PermissionChecker
 }
 
 // NewUserServer 创建用户服务的 gRPC 服务端
-func NewUserServer(userService *service.UserService) *UserServer {
+func NewUserServer(userService *service.UserService, rbacService *service.RBACService) *UserServer {
 	return &UserServer{
 		userService: userService,
+		rbacService: rbacService,
+		permissions: rbacService,
 	}
 }
 
@@ -37,13 +39,10 @@ func (s *UserServer) CreateUser(ctx context.Context, req *protobuf.CreateUserReq
 		Phone:    req.Phone,
 	}
 
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
-
 	// 调用服务层
-	user, err := s.userService.Create(createReq)
+	user, err := s.userService.WithContext(ctx).Create(createReq)
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 转换响应
@@ -54,13 +53,14 @@ func (s *UserServer) CreateUser(ctx context.Context, req *protobuf.CreateUserReq
 
 // GetUser 获取用户
 func (s *UserServer) GetUser(ctx context.Context, req *protobuf.GetUserRequest) (*protobuf.GetUserResponse, error) {
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
+	if err := requirePermission(ctx, s.permissions, model.PermissionUsersRead); err != nil {
+		return nil, err
+	}
 
 	// 调用服务层
-	user, err := s.userService.GetByID(uint(req.Id))
+	user, err := s.userService.WithContext(ctx).GetByID(uint(req.Id))
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 转换响应
@@ -71,6 +71,10 @@ func (s *UserServer) GetUser(ctx context.Context, req *protobuf.GetUserRequest)
 
 // UpdateUser 更新用户
 func (s *UserServer) UpdateUser(ctx context.Context, req *protobuf.UpdateUserRequest) (*protobuf.UpdateUserResponse, error) {
+	if err := requirePermission(ctx, s.permissions, model.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
+
 	// 转换请求
 	updateReq := &model.UpdateUserRequest{
 		Name:   req.Name,
@@ -78,13 +82,10 @@ func (s *UserServer) UpdateUser(ctx context.Context, req *protobuf.UpdateUserReq
 		Avatar: req.Avatar,
 	}
 
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
-
 	// 调用服务层
-	user, err := s.userService.Update(uint(req.Id), updateReq)
+	user, err := s.userService.WithContext(ctx).Update(uint(req.Id), updateReq)
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 转换响应
@@ -95,13 +96,14 @@ func (s *UserServer) UpdateUser(ctx context.Context, req *protobuf.UpdateUserReq
 
 // DeleteUser 删除用户
 func (s *UserServer) DeleteUser(ctx context.Context, req *protobuf.DeleteUserRequest) (*protobuf.DeleteUserResponse, error) {
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
+	if err := requirePermission(ctx, s.permissions, model.PermissionUsersWrite); err != nil {
+		return nil, err
+	}
 
 	// 调用服务层
-	err := s.userService.Delete(uint(req.Id))
+	err := s.userService.WithContext(ctx).Delete(uint(req.Id))
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 返回响应
@@ -112,6 +114,10 @@ func (s *UserServer) DeleteUser(ctx context.Context, req *protobuf.DeleteUserReq
 
 // ListUsers 获取用户列表
 func (s *UserServer) ListUsers(ctx context.Context, req *protobuf.ListUsersRequest) (*protobuf.ListUsersResponse, error) {
+	if err := requirePermission(ctx, s.permissions, model.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
 	// 转换请求
 	listReq := &model.UserListRequest{
 		PageRequest: model.PageRequest{
@@ -136,13 +142,10 @@ func (s *UserServer) ListUsers(ctx context.Context, req *protobuf.ListUsersReque
 		listReq.Size = 10
 	}
 
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
-
 	// 调用服务层
-	users, total, err := s.userService.List(listReq)
+	users, total, err := s.userService.WithContext(ctx).List(listReq)
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 转换响应
@@ -167,19 +170,21 @@ func (s *UserServer) Login(ctx context.Context, req *protobuf.LoginRequest) (*pr
 		Password: req.Password,
 	}
 
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
-
 	// 调用服务层
-	user, err := s.userService.Login(loginReq)
+	user, err := s.userService.WithContext(ctx).Login(loginReq)
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 生成 JWT token
-	token, err := middleware.GenerateToken(user.ID, user.Username)
+	roles, err := s.rbacService.GetUserRoleNames(ctx, user.ID)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "Failed to generate token")
+		return nil, err
+	}
+
+	token, err := middleware.GenerateToken(user.ID, user.Username, roles)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to generate token", err)
 	}
 
 	// 转换响应
@@ -189,15 +194,20 @@ func (s *UserServer) Login(ctx context.Context, req *protobuf.LoginRequest) (*pr
 	}, nil
 }
 
-// ChangePassword 修改密码
+// ChangePassword 修改当前登录用户的密码，user_id 为空或与 JWT 中的用户一致
 func (s *UserServer) ChangePassword(ctx context.Context, req *protobuf.ChangePasswordRequest) (*protobuf.ChangePasswordResponse, error) {
-	// 设置上下文
-	s.userService.BaseService.Ctx = ctx
+	claims := interceptor.ClaimsFromContext(ctx)
+	if claims == nil {
+		return nil, errorsx.New(errorsx.CodeUnauthorized, "unauthorized")
+	}
+	if req.UserId != 0 && req.UserId != claims.UserID {
+		return nil, errorsx.New(errorsx.CodeForbidden, "Cannot change another user's password")
+	}
 
 	// 调用服务层
-	err := s.userService.ChangePassword(uint(req.UserId), req.OldPassword, req.NewPassword)
+	err := s.userService.WithContext(ctx).ChangePassword(uint(claims.UserID), req.OldPassword, req.NewPassword)
 	if err != nil {
-		return nil, convertError(err)
+		return nil, err
 	}
 
 	// 返回响应
@@ -228,37 +238,3 @@ func convertUserToProto(user *model.User) *protobuf.User {
 
 	return protoUser
 }
-
-// convertError 转换错误为 gRPC 错误
-func convertError(err error) error {
-	if err == nil {
-		return nil
-	}
-
-	// 检查是否是自定义错误
-	if appErr, ok := err.(*errorsx.AppError); ok {
-		switch appErr.Code {
-		case errorsx.CodeUserNotFound:
-			return status.Error(codes.NotFound, appErr.Message)
-		case errorsx.CodeUserAlreadyExists:
-			return status.Error(codes.AlreadyExists, appErr.Message)
-		case errorsx.CodeInvalidCredentials:
-			return status.Error(codes.Unauthenticated, appErr.Message)
-		case errorsx.CodeUserDisabled:
-			return status.Error(codes.PermissionDenied, appErr.Message)
-		case errorsx.CodeValidationFailed:
-			return status.Error(codes.InvalidArgument, appErr.Message)
-		case errorsx.CodeBadRequest:
-			return status.Error(codes.InvalidArgument, appErr.Message)
-		case errorsx.CodeDatabaseError:
-			return status.Error(codes.Internal, appErr.Message)
-		case errorsx.CodeInternalServerError:
-			return status.Error(codes.Internal, appErr.Message)
-		default:
-			return status.Error(codes.Internal, appErr.Message)
-		}
-	}
-
-	// 默认返回内部错误
-	return status.Error(codes.Internal, err.Error())
-}
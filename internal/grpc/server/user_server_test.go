@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/iswangwenbin/gin-starter/internal/grpc/interceptor"
+	"github.com/iswangwenbin/gin-starter/internal/grpc/protobuf"
+	"github.com/iswangwenbin/gin-starter/internal/middleware"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type callerKey struct{}
+
+// ctxDriver 按查询所带 context 中的调用方返回用户名，用于确认每次查询使用的是本次请求的 context
+type ctxDriver struct{}
+
+func (ctxDriver) Open(string) (driver.Conn, error) { return ctxConn{}, nil }
+
+type ctxConn struct{}
+
+func (ctxConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (ctxConn) Close() error                        { return nil }
+func (ctxConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (ctxConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return &ctxRows{values: []driver.Value{int64(1), caller}}, nil
+}
+
+type ctxRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *ctxRows) Columns() []string { return []string{"id", "username"} }
+func (r *ctxRows) Close() error      { return nil }
+
+func (r *ctxRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+var registerCtxDriver sync.Once
+
+func newTestUserServer(t *testing.T) *UserServer {
+	registerCtxDriver.Do(func() { sql.Register("ctxstub", ctxDriver{}) })
+	sqlDB, err := sql.Open("ctxstub", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := service.NewBaseService(repository.NewRepository(db), nil, zap.NewNop())
+	s := NewUserServer(service.NewUserService(base), service.NewRBACService(base))
+	s.permissions = rolePermissions{
+		"admin":  {model.PermissionUsersRead, model.PermissionUsersWrite},
+		"viewer": {model.PermissionUsersRead},
+	}
+	return s
+}
+
+// rolePermissions 固定的角色权限映射，替代依赖数据库的 RBACService
+type rolePermissions map[string][]string
+
+func (r rolePermissions) HasPermission(_ context.Context, roles []string, permission string) (bool, error) {
+	for _, role := range roles {
+		for _, p := range r[role] {
+			if p == permission {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func withUser(ctx context.Context, userID uint64, roles ...string) context.Context {
+	return interceptor.ContextWithClaims(ctx, &middleware.Claims{UserID: userID, Username: "user", Roles: roles})
+}
+
+// 并发请求各自使用自己的 context，配合 go test -race 检查共享状态
+func TestUserServerConcurrentContext(t *testing.T) {
+	s := newTestUserServer(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(caller string) {
+			defer wg.Done()
+			ctx := withUser(context.WithValue(context.Background(), callerKey{}, caller), 1, "viewer")
+			resp, err := s.GetUser(ctx, &protobuf.GetUserRequest{Id: 1})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if resp.User.Username != caller {
+				errs <- fmt.Errorf("caller %s: query ran with context of %s", caller, resp.User.Username)
+			}
+		}(fmt.Sprintf("caller-%d", i))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// 用户管理方法要求与 HTTP 接口相同的权限，只有有效 JWT 不足以访问
+func TestUserServerRequiresPermission(t *testing.T) {
+	s := newTestUserServer(t)
+
+	calls := map[string]func(ctx context.Context) error{
+		"GetUser": func(ctx context.Context) error {
+			_, err := s.GetUser(ctx, &protobuf.GetUserRequest{Id: 2})
+			return err
+		},
+		"ListUsers": func(ctx context.Context) error {
+			_, err := s.ListUsers(ctx, &protobuf.ListUsersRequest{})
+			return err
+		},
+		"UpdateUser": func(ctx context.Context) error {
+			_, err := s.UpdateUser(ctx, &protobuf.UpdateUserRequest{Id: 2, Name: "x"})
+			return err
+		},
+		"DeleteUser": func(ctx context.Context) error {
+			_, err := s.DeleteUser(ctx, &protobuf.DeleteUserRequest{Id: 2})
+			return err
+		},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		ctx    context.Context
+		want   codes.Code
+	}{
+		{name: "get without role", method: "GetUser", ctx: withUser(context.Background(), 1), want: codes.PermissionDenied},
+		{name: "list without role", method: "ListUsers", ctx: withUser(context.Background(), 1), want: codes.PermissionDenied},
+		{name: "update as viewer", method: "UpdateUser", ctx: withUser(context.Background(), 1, "viewer"), want: codes.PermissionDenied},
+		{name: "delete as viewer", method: "DeleteUser", ctx: withUser(context.Background(), 1, "viewer"), want: codes.PermissionDenied},
+		{name: "delete without role", method: "DeleteUser", ctx: withUser(context.Background(), 1), want: codes.PermissionDenied},
+		{name: "get without claims", method: "GetUser", ctx: context.Background(), want: codes.Unauthenticated},
+		{name: "get as viewer", method: "GetUser", ctx: withUser(context.Background(), 1, "viewer"), want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := calls[tt.method](tt.ctx)
+			if got := status.Code(errorsx.ToGRPC(err)); got != tt.want {
+				t.Errorf("%s() code = %s, want %s (err = %v)", tt.method, got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestUserServerChangePasswordOwnAccountOnly(t *testing.T) {
+	s := newTestUserServer(t)
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		userID uint64
+		want   codes.Code
+	}{
+		{name: "other user", ctx: withUser(context.Background(), 1, "admin"), userID: 2, want: codes.PermissionDenied},
+		{name: "without claims", ctx: context.Background(), userID: 1, want: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ChangePassword(tt.ctx, &protobuf.ChangePasswordRequest{UserId: tt.userID, OldPassword: "old", NewPassword: "new-password"})
+			if got := status.Code(errorsx.ToGRPC(err)); got != tt.want {
+				t.Errorf("ChangePassword() code = %s, want %s (err = %v)", got, tt.want, err)
+			}
+		})
+	}
+}
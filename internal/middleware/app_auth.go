@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/iswangwenbin/gin-starter/pkg/signx"
+)
+
+// AppIDKey 签名认证通过后写入上下文的应用 ID
+const AppIDKey = "app_id"
+
+// maxSignedBodySize 签名请求体大小上限
+const maxSignedBodySize = 32 << 20
+
+// AppAuthenticator 校验上报请求签名
+type AppAuthenticator interface {
+	Authenticate(ctx context.Context, sig *model.AppSignature, payload []byte) (*model.App, error)
+}
+
+// AppSignatureAuth 校验事件上报请求的 HMAC 签名，签名覆盖原始请求体和时间戳
+func AppSignatureAuth(authenticator AppAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg := configx.GetConfig(); cfg != nil && !cfg.AppAuth.Enabled {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodySize))
+		if err != nil {
+			abortWithAppError(c, errorsx.New(errorsx.CodeBadRequest, "Failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		timestamp, _ := strconv.ParseInt(c.GetHeader(signx.HeaderTimestamp), 10, 64)
+		sig := &model.AppSignature{
+			AppID:     c.GetHeader(signx.HeaderAppID),
+			KeyID:     c.GetHeader(signx.HeaderKeyID),
+			Timestamp: timestamp,
+			Signature: c.GetHeader(signx.HeaderSignature),
+		}
+
+		app, err := authenticator.Authenticate(c.Request.Context(), sig, body)
+		if err != nil {
+			abortWithAppError(c, err)
+			return
+		}
+
+		c.Set(AppIDKey, app.AppID)
+		c.Next()
+	}
+}
+
+func abortWithAppError(c *gin.Context, err error) {
+	var appErr *errorsx.AppError
+	if !errors.As(err, &appErr) {
+		appErr = errorsx.NewWithError(errorsx.CodeInternalServerError, "app authentication failed", err)
+	}
+
+	c.JSON(appErr.GetHTTPStatus(), gin.H{
+		"code":    int(appErr.Code),
+		"message": appErr.Message,
+		"data":    nil,
+	})
+	c.Abort()
+}
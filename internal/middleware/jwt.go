@@ -8,44 +8,59 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/jwtx"
 )
 
+// ClaimsKey 解析后的 JWT Claims 在 gin.Context 中的键
+const ClaimsKey = "claims"
+
 type Claims struct {
-	UserID   uint64 `json:"user_id"`
-	Username string `json:"username"`
+	UserID   uint64   `json:"user_id"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint64, username string) (string, error) {
+func GenerateToken(userID uint64, username string, roles []string) (string, error) {
 	cfg := configx.GetConfig()
 	if cfg == nil {
 		return "", errors.New("config not loaded")
 	}
 
+	// jti 使用 UUIDv7，携带毫秒级签发时间，用于与用户吊销时间比较
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
 	claims := Claims{
 		UserID:   userID,
 		Username: username,
+		Roles:    roles,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.Expires)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        id.String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(cfg.JWT.Expires)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.JWT.Secret))
+	keySet, err := jwtx.Default()
+	if err != nil {
+		return "", err
+	}
+	return keySet.Sign(claims)
 }
 
 func ParseToken(tokenString string) (*Claims, error) {
-	cfg := configx.GetConfig()
-	if cfg == nil {
-		return nil, errors.New("config not loaded")
+	keySet, err := jwtx.Default()
+	if err != nil {
+		return nil, err
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.JWT.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keySet.Keyfunc, keySet.ParserOptions()...)
 
 	if err != nil {
 		return nil, err
@@ -58,7 +73,7 @@ func ParseToken(tokenString string) (*Claims, error) {
 	return nil, jwt.ErrInvalidKey
 }
 
-func JWTAuth() gin.HandlerFunc {
+func JWTAuth(denylist *TokenDenylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -94,13 +109,35 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := denylist.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "token revocation check failed",
+				"data":    nil,
+			})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": "token has been revoked",
+				"data":    nil,
+			})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("roles", claims.Roles)
+		c.Set(ClaimsKey, claims)
 		c.Next()
 	}
 }
 
-func JWTAuthOptional() gin.HandlerFunc {
+func JWTAuthOptional(denylist *TokenDenylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -121,8 +158,24 @@ func JWTAuthOptional() gin.HandlerFunc {
 			return
 		}
 
+		// 已吊销或无法确认时按未登录处理
+		if revoked, err := denylist.IsRevoked(c.Request.Context(), claims); err != nil || revoked {
+			c.Next()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("roles", claims.Roles)
+		c.Set(ClaimsKey, claims)
 		c.Next()
 	}
 }
+
+// GetClaims 从 context 获取 JWT Claims
+func GetClaims(c *gin.Context) *Claims {
+	if claims, exists := c.Get(ClaimsKey); exists {
+		return claims.(*Claims)
+	}
+	return nil
+}
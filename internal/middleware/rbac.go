@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+)
+
+// PermissionChecker 根据角色判断权限
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, roles []string, permission string) (bool, error)
+}
+
+// RequirePermission 要求当前用户拥有指定权限，需放在 JWTAuth 之后
+func RequirePermission(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := GetClaims(c)
+		if claims == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": "unauthorized",
+				"data":    nil,
+			})
+			c.Abort()
+			return
+		}
+
+		allowed, err := checker.HasPermission(c.Request.Context(), claims.Roles, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "permission check failed",
+				"data":    nil,
+			})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			appErr := errorsx.ErrInsufficientPermission
+			c.JSON(appErr.GetHTTPStatus(), gin.H{
+				"code":    int(appErr.Code),
+				"message": appErr.Message,
+				"data":    gin.H{"required_permission": permission},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist 基于 Redis 的访问令牌吊销列表
+type TokenDenylist struct {
+	Redis *redis.Client
+}
+
+func NewTokenDenylist(redisClient *redis.Client) *TokenDenylist {
+	return &TokenDenylist{
+		Redis: redisClient,
+	}
+}
+
+// Revoke 吊销单个访问令牌，记录保留到令牌自然过期
+func (d *TokenDenylist) Revoke(ctx context.Context, claims *Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return d.Redis.Set(ctx, tokenDenylistKey(claims.ID), 1, ttl).Err()
+}
+
+// RevokeUser 吊销用户在此刻之前签发的所有访问令牌，吊销时间精确到毫秒
+func (d *TokenDenylist) RevokeUser(ctx context.Context, userID uint64) error {
+	ttl := 24 * time.Hour
+	if cfg := configx.GetConfig(); cfg != nil && cfg.JWT.Expires > 0 {
+		ttl = cfg.JWT.Expires
+	}
+
+	return d.Redis.Set(ctx, userDenylistKey(userID), time.Now().UnixMilli(), ttl).Err()
+}
+
+// IsRevoked 检查令牌是否已被吊销
+func (d *TokenDenylist) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	if d == nil || d.Redis == nil {
+		return false, nil
+	}
+
+	pipe := d.Redis.Pipeline()
+	var tokenCmd *redis.IntCmd
+	if claims.ID != "" {
+		tokenCmd = pipe.Exists(ctx, tokenDenylistKey(claims.ID))
+	}
+	userCmd := pipe.Get(ctx, userDenylistKey(claims.UserID))
+
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if tokenCmd != nil && tokenCmd.Val() > 0 {
+		return true, nil
+	}
+
+	if userCmd.Err() == nil && claims.IssuedAt != nil {
+		revokedAt, err := strconv.ParseInt(userCmd.Val(), 10, 64)
+		if err != nil {
+			return false, err
+		}
+		// 升级前写入的吊销时间为秒，按该秒的最后一毫秒处理
+		if revokedAt < 1e12 {
+			revokedAt = revokedAt*1000 + 999
+		}
+		if issuedAtMillis(claims) <= revokedAt {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// issuedAtMillis 令牌的毫秒级签发时间：jti 为 UUIDv7 时取其时间戳，否则取精确到秒的 iat
+func issuedAtMillis(claims *Claims) int64 {
+	if id, err := uuid.Parse(claims.ID); err == nil && id.Version() == 7 {
+		return int64(binary.BigEndian.Uint64(id[:8]) >> 16)
+	}
+	return claims.IssuedAt.Unix() * 1000
+}
+
+func tokenDenylistKey(tokenID string) string {
+	return fmt.Sprintf("token_denylist:%s", tokenID)
+}
+
+func userDenylistKey(userID uint64) string {
+	return fmt.Sprintf("token_denylist:user:%d", userID)
+}
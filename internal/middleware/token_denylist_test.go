@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestIssuedAtMillis(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().UnixMilli()
+
+	iat := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		id       string
+		min, max int64
+	}{
+		{name: "uuidv7", id: id.String(), min: before, max: after},
+		{name: "uuidv4 falls back to iat", id: uuid.NewString(), min: iat.UnixMilli(), max: iat.UnixMilli()},
+		{name: "empty falls back to iat", id: "", min: iat.UnixMilli(), max: iat.UnixMilli()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &Claims{RegisteredClaims: jwt.RegisteredClaims{ID: tt.id, IssuedAt: jwt.NewNumericDate(iat)}}
+			if got := issuedAtMillis(claims); got < tt.min || got > tt.max {
+				t.Errorf("issuedAtMillis() = %d, want [%d, %d]", got, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"go.uber.org/zap"
+)
+
+// ClickHouseDir ClickHouse 迁移文件的源码目录，相对于项目根目录
+const ClickHouseDir = "internal/migration/clickhouse"
+
+//go:embed clickhouse/*.sql
+var clickhouseFS embed.FS
+
+// ClickHouseVars ClickHouse 迁移模板中可用的变量
+type ClickHouseVars struct {
+	RetentionDays int // install_events 建表时的 TTL 天数，0 表示不设置 TTL
+}
+
+// clickhouseDriver 迁移记录保存在 schema_migrations 表中
+// ClickHouse 不支持行级更新，每次状态变化插入一行，按 updated_at 取最新一行，applied = 0 表示已回滚
+type clickhouseDriver struct {
+	conn clickhouse.Conn
+}
+
+// NewClickHouseMigrator 创建 ClickHouse 迁移器，迁移文件内嵌在 clickhouse 目录
+func NewClickHouseMigrator(conn clickhouse.Conn, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := Load(clickhouseFS, "clickhouse")
+	if err != nil {
+		return nil, err
+	}
+
+	vars := ClickHouseVars{}
+	// 只有 ttl 模式的默认保留天数写入建表语句，按应用的规则由 events retention apply 设置
+	if cfg := configx.GetConfig(); cfg != nil && cfg.Retention.Mode == "ttl" {
+		vars.RetentionDays = cfg.Retention.DefaultDays
+	}
+	return NewMigrator(&clickhouseDriver{conn: conn}, migrations, vars, logger), nil
+}
+
+func (d *clickhouseDriver) Name() string {
+	return "clickhouse"
+}
+
+func (d *clickhouseDriver) Init(ctx context.Context) error {
+	err := d.conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations
+		(
+			version    UInt64,
+			name       String,
+			checksum   String,
+			dirty      UInt8,
+			applied    UInt8,
+			updated_at DateTime64(6, 'UTC')
+		)
+		ENGINE = ReplacingMergeTree(updated_at)
+		ORDER BY version`)
+	if err != nil {
+		return fmt.Errorf("create clickhouse schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// Lock ClickHouse 没有咨询锁，由部署流程保证同一时间只有一个进程执行迁移
+func (d *clickhouseDriver) Lock(ctx context.Context) (func(), error) {
+	return func() {}, nil
+}
+
+func (d *clickhouseDriver) Records(ctx context.Context) ([]Record, error) {
+	rows, err := d.conn.Query(ctx, `
+		SELECT version, name, checksum, dirty, updated_at
+		FROM (
+			SELECT
+				version,
+				argMax(name, updated_at) AS name,
+				argMax(checksum, updated_at) AS checksum,
+				argMax(dirty, updated_at) AS dirty,
+				argMax(applied, updated_at) AS applied,
+				max(updated_at) AS updated_at
+			FROM schema_migrations
+			GROUP BY version
+		)
+		WHERE applied = 1
+		ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("read clickhouse schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		var dirty uint8
+		if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &dirty, &record.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan clickhouse schema_migrations: %w", err)
+		}
+		record.Dirty = dirty == 1
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (d *clickhouseDriver) SetRecord(ctx context.Context, record Record) error {
+	return d.insert(ctx, record, true)
+}
+
+func (d *clickhouseDriver) DeleteRecord(ctx context.Context, version uint64) error {
+	return d.insert(ctx, Record{Version: version, UpdatedAt: time.Now().UTC()}, false)
+}
+
+func (d *clickhouseDriver) insert(ctx context.Context, record Record, applied bool) error {
+	err := d.conn.Exec(ctx,
+		"INSERT INTO schema_migrations (version, name, checksum, dirty, applied, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		record.Version, record.Name, record.Checksum, boolToUInt8(record.Dirty), boolToUInt8(applied), record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("write clickhouse schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (d *clickhouseDriver) Exec(ctx context.Context, statement string) error {
+	return d.conn.Exec(ctx, statement)
+}
+
+func boolToUInt8(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+-- 安装事件明细表
+-- ReplacingMergeTree 按 (app_id, event_date, event_time, event_id) 去重，Worker 重投的同一事件在合并后只保留一行；
+-- 查询均带 app_id 和时间范围，排序键前缀与之匹配，event_date 按月分区用于分区裁剪和按月清理
+CREATE TABLE IF NOT EXISTS install_events
+(
+    app_id            LowCardinality(String),
+    app_name          LowCardinality(String),
+    app_version       LowCardinality(String),
+    app_type          UInt8,
+    event_id          String,
+    event_date        Date,
+    event_time        DateTime64(3, 'UTC'),
+    device_id         String,
+    channel_id        LowCardinality(String),
+    install_ip        String,
+    install_type      UInt8,
+    install_result    UInt8,
+    os_language       LowCardinality(String),
+    os_timezone       LowCardinality(String),
+    os_name           LowCardinality(String),
+    os_version        LowCardinality(String),
+    os_build          LowCardinality(String),
+    os_family         LowCardinality(String),
+    signature_status  UInt8,
+    signature_version LowCardinality(String),
+    signature_params  Map(String, String),
+    inserted_at       DateTime64(3, 'UTC') DEFAULT now64(3),
+
+    INDEX idx_device_id device_id TYPE bloom_filter(0.01) GRANULARITY 4,
+    INDEX idx_channel_id channel_id TYPE bloom_filter(0.01) GRANULARITY 4
+)
+ENGINE = ReplacingMergeTree(inserted_at)
+PARTITION BY toYYYYMM(event_date)
+ORDER BY (app_id, event_date, event_time, event_id)
+{{- if .RetentionDays }}
+TTL event_date + INTERVAL {{ .RetentionDays }} DAY DELETE
+{{- end }}
+SETTINGS index_granularity = 8192, non_replicated_deduplication_window = 1000;
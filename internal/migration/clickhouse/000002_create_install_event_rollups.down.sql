@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS install_events_daily_mv;
+DROP VIEW IF EXISTS install_events_hourly_mv;
+DROP TABLE IF EXISTS install_events_daily;
+DROP TABLE IF EXISTS install_events_hourly;
+DROP TABLE IF EXISTS install_events_rollup_cutoff;
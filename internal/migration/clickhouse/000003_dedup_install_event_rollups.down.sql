@@ -0,0 +1,30 @@
+DROP TABLE IF EXISTS install_events_daily_rebuild;
+DROP TABLE IF EXISTS install_events_hourly_rebuild;
+
+ALTER TABLE install_events_daily_mv MODIFY QUERY
+SELECT
+    app_id,
+    channel_id,
+    app_type,
+    install_result,
+    toDate(event_time, 'UTC') AS day,
+    count() AS events,
+    countIf(install_type = 1) AS first_installs,
+    countIf(install_type = 2) AS repeat_installs,
+    uniqCombinedState(device_id) AS devices
+FROM install_events
+GROUP BY app_id, channel_id, app_type, install_result, day;
+
+ALTER TABLE install_events_hourly_mv MODIFY QUERY
+SELECT
+    app_id,
+    channel_id,
+    app_type,
+    install_result,
+    toStartOfHour(toDateTime(event_time, 'UTC')) AS hour,
+    count() AS events,
+    countIf(install_type = 1) AS first_installs,
+    countIf(install_type = 2) AS repeat_installs,
+    uniqCombinedState(device_id) AS devices
+FROM install_events
+GROUP BY app_id, channel_id, app_type, install_result, hour;
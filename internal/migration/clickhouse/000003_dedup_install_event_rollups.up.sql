@@ -0,0 +1,47 @@
+-- 物化视图与回填使用相同的去重规则：按 install_events 的排序键 (app_id, event_date, event_time, event_id)
+-- 每个事件只计一次，保留 inserted_at 最大的一行，与 ReplacingMergeTree(inserted_at) 合并后的结果一致。
+-- 物化视图只能看到本次写入的数据块，块内重复在此去除，跨批次重复仍需回填修正，见 README
+ALTER TABLE install_events_hourly_mv MODIFY QUERY
+SELECT
+    app_id,
+    channel_id,
+    app_type,
+    install_result,
+    toStartOfHour(toDateTime(event_time, 'UTC')) AS hour,
+    count() AS events,
+    countIf(install_type = 1) AS first_installs,
+    countIf(install_type = 2) AS repeat_installs,
+    uniqCombinedState(device_id) AS devices
+FROM
+(
+    SELECT app_id, channel_id, app_type, install_result, install_type, event_time, device_id
+    FROM install_events
+    ORDER BY inserted_at DESC
+    LIMIT 1 BY app_id, event_date, event_time, event_id
+)
+GROUP BY app_id, channel_id, app_type, install_result, hour;
+
+ALTER TABLE install_events_daily_mv MODIFY QUERY
+SELECT
+    app_id,
+    channel_id,
+    app_type,
+    install_result,
+    toDate(event_time, 'UTC') AS day,
+    count() AS events,
+    countIf(install_type = 1) AS first_installs,
+    countIf(install_type = 2) AS repeat_installs,
+    uniqCombinedState(device_id) AS devices
+FROM
+(
+    SELECT app_id, channel_id, app_type, install_result, install_type, event_time, device_id
+    FROM install_events
+    ORDER BY inserted_at DESC
+    LIMIT 1 BY app_id, event_date, event_time, event_id
+)
+GROUP BY app_id, channel_id, app_type, install_result, day;
+
+-- 回填时先写入临时表，完成后以 REPLACE PARTITION 原子替换汇总表中的分区
+CREATE TABLE IF NOT EXISTS install_events_hourly_rebuild AS install_events_hourly;
+
+CREATE TABLE IF NOT EXISTS install_events_daily_rebuild AS install_events_daily;
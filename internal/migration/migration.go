@@ -0,0 +1,453 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fileNamePattern 迁移文件名格式：<版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的升级和回滚 SQL
+type Migration struct {
+	Version  uint64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // 升级 SQL 原文的摘要，用于发现已执行的迁移被修改
+}
+
+// Record 记录表中的迁移状态
+type Record struct {
+	Version   uint64
+	Name      string
+	Checksum  string
+	Dirty     bool // 执行中断，需要人工确认后 force
+	UpdatedAt time.Time
+}
+
+// Status 单个迁移的执行状态
+type Status struct {
+	Version   uint64    `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	Dirty     bool      `json:"dirty"`
+	Modified  bool      `json:"modified"` // 已执行后文件内容被修改
+	Missing   bool      `json:"missing"`  // 记录表中存在但迁移文件已删除
+	AppliedAt time.Time `json:"applied_at,omitempty"`
+}
+
+// Driver 数据库相关的操作，记录表中只保存已执行（含中断）的版本
+type Driver interface {
+	Name() string
+	// Init 创建迁移记录表
+	Init(ctx context.Context) error
+	// Lock 防止多个进程同时执行迁移，返回释放函数
+	Lock(ctx context.Context) (func(), error)
+	Records(ctx context.Context) ([]Record, error)
+	SetRecord(ctx context.Context, record Record) error
+	DeleteRecord(ctx context.Context, version uint64) error
+	Exec(ctx context.Context, statement string) error
+}
+
+// Migrator 按版本顺序执行迁移
+type Migrator struct {
+	driver     Driver
+	migrations []Migration
+	vars       interface{}
+	logger     *zap.Logger
+}
+
+// NewMigrator 创建迁移器，vars 不为空时迁移 SQL 先作为 text/template 渲染
+func NewMigrator(driver Driver, migrations []Migration, vars interface{}, logger *zap.Logger) *Migrator {
+	return &Migrator{
+		driver:     driver,
+		migrations: migrations,
+		vars:       vars,
+		logger:     logger,
+	}
+}
+
+// Load 从 dir 目录加载迁移文件，版本号不能重复且必须有升级 SQL
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected <version>_<name>.(up|down).sql", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+			sum := sha256.Sum256(data)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// namePattern 新建迁移的名称只允许小写字母、数字和下划线
+var namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create 在 dir 目录下创建下一个版本号的空迁移文件，返回创建的文件路径
+func Create(dir, name string) ([]string, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q, use lowercase letters, digits and underscores", name)
+	}
+
+	migrations, err := Load(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, err
+	}
+	var version uint64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	var files []string
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- %06d_%s %s\n", version, name, direction)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return files, fmt.Errorf("create migration file: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Status 返回所有迁移的执行状态，按版本排序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.driver.Init(ctx); err != nil {
+		return nil, err
+	}
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := make(map[uint64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := records[migration.Version]; ok {
+			status.Applied = !record.Dirty
+			status.Dirty = record.Dirty
+			status.Modified = record.Checksum != "" && record.Checksum != migration.Checksum
+			status.AppliedAt = record.UpdatedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range records {
+		if !known[version] {
+			statuses = append(statuses, Status{
+				Version: version, Name: record.Name, Applied: !record.Dirty, Dirty: record.Dirty,
+				Missing: true, AppliedAt: record.UpdatedAt,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Pending 返回尚未执行的迁移数量，存在中断的迁移时返回错误
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, status := range statuses {
+		if status.Dirty {
+			return 0, fmt.Errorf("%s migration %d is dirty, fix the schema and run force", m.driver.Name(), status.Version)
+		}
+		if !status.Applied && !status.Missing {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// Up 依次执行未执行的迁移，steps 为 0 时全部执行，返回执行的迁移
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	unlock, err := m.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDirty(m.driver.Name(), records); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range m.migrations {
+		if _, ok := records[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(applied) >= steps {
+			break
+		}
+		if err := m.run(ctx, migration, migration.Up, true); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down 按版本倒序回滚已执行的迁移，steps 为 0 时回滚一个
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	unlock, err := m.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDirty(m.driver.Name(), records); err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := records[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return reverted, fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		if err := m.run(ctx, migration, migration.Down, false); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// Force 将指定版本及之前的迁移标记为已执行、之后的标记为未执行，不执行任何 SQL，用于修复中断的迁移
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	unlock, err := m.prepare(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	found := version == 0
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown %s migration version %d", m.driver.Name(), version)
+	}
+
+	records, err := m.records(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range m.migrations {
+		record, ok := records[migration.Version]
+		switch {
+		case migration.Version <= version && (!ok || record.Dirty):
+			err = m.driver.SetRecord(ctx, Record{Version: migration.Version, Name: migration.Name, Checksum: migration.Checksum, UpdatedAt: time.Now().UTC()})
+		case migration.Version > version && ok:
+			err = m.driver.DeleteRecord(ctx, migration.Version)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	m.logger.Info("Migration version forced", zap.String("driver", m.driver.Name()), zap.Uint64("version", version))
+	return nil
+}
+
+func (m *Migrator) prepare(ctx context.Context) (func(), error) {
+	if err := m.driver.Init(ctx); err != nil {
+		return nil, err
+	}
+	return m.driver.Lock(ctx)
+}
+
+func (m *Migrator) records(ctx context.Context) (map[uint64]Record, error) {
+	list, err := m.driver.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[uint64]Record, len(list))
+	for _, record := range list {
+		records[record.Version] = record
+	}
+	return records, nil
+}
+
+// run 执行前先记录为中断状态，全部语句成功后再更新记录，失败时保留中断状态
+func (m *Migrator) run(ctx context.Context, migration Migration, sql string, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+	statements, err := m.statements(sql)
+	if err != nil {
+		return fmt.Errorf("render migration %d_%s.%s.sql: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	dirty := Record{Version: migration.Version, Name: migration.Name, Checksum: migration.Checksum, Dirty: true, UpdatedAt: time.Now().UTC()}
+	if err := m.driver.SetRecord(ctx, dirty); err != nil {
+		return err
+	}
+
+	started := time.Now()
+	for _, statement := range statements {
+		if err := m.driver.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("migration %d_%s.%s.sql failed, schema is dirty: %w", migration.Version, migration.Name, direction, err)
+		}
+	}
+
+	if up {
+		dirty.Dirty = false
+		dirty.UpdatedAt = time.Now().UTC()
+		err = m.driver.SetRecord(ctx, dirty)
+	} else {
+		err = m.driver.DeleteRecord(ctx, migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("Migration applied",
+		zap.String("driver", m.driver.Name()),
+		zap.String("direction", direction),
+		zap.Uint64("version", migration.Version),
+		zap.String("name", migration.Name),
+		zap.Duration("duration", time.Since(started)))
+	return nil
+}
+
+func (m *Migrator) statements(sql string) ([]string, error) {
+	if m.vars != nil {
+		tmpl, err := template.New("migration").Option("missingkey=error").Parse(sql)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, m.vars); err != nil {
+			return nil, err
+		}
+		sql = buf.String()
+	}
+	return SplitStatements(sql), nil
+}
+
+func checkDirty(driver string, records map[uint64]Record) error {
+	for _, record := range records {
+		if record.Dirty {
+			return fmt.Errorf("%s migration %d is dirty, fix the schema and run force", driver, record.Version)
+		}
+	}
+	return nil
+}
+
+// SplitStatements 按分号拆分 SQL，忽略单引号字符串中的分号和整行 -- 注释
+func SplitStatements(sql string) []string {
+	var lines []string
+	for _, line := range strings.Split(sql, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	sql = strings.Join(lines, "\n")
+
+	var statements []string
+	var current strings.Builder
+	inQuote := false
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case ch == '\\' && inQuote && i+1 < len(sql):
+			current.WriteByte(ch)
+			i++
+			ch = sql[i]
+		case ch == '\'':
+			inQuote = !inQuote
+		case ch == ';' && !inQuote:
+			if statement := strings.TrimSpace(current.String()); statement != "" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteByte(ch)
+	}
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "multiple statements",
+			sql:  "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want: []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name: "last statement without semicolon",
+			sql:  "DROP TABLE a;\nDROP TABLE b",
+			want: []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{
+			name: "semicolon inside string",
+			sql:  "INSERT INTO t VALUES ('a;b');\nSELECT 1;",
+			want: []string{"INSERT INTO t VALUES ('a;b')", "SELECT 1"},
+		},
+		{
+			name: "escaped quote inside string",
+			sql:  `INSERT INTO t VALUES ('it\'s;fine'); SELECT 2;`,
+			want: []string{`INSERT INTO t VALUES ('it\'s;fine')`, "SELECT 2"},
+		},
+		{
+			name: "full line comments removed",
+			sql:  "-- create a; with semicolon\nCREATE TABLE a (id INT);\n  -- indented comment;\nSELECT 1;",
+			want: []string{"CREATE TABLE a (id INT)", "SELECT 1"},
+		},
+		{
+			name: "multi-line statement",
+			sql:  "CREATE TABLE a (\n    id INT,\n    name VARCHAR(10)\n);",
+			want: []string{"CREATE TABLE a (\n    id INT,\n    name VARCHAR(10)\n)"},
+		},
+		{
+			name: "empty statements skipped",
+			sql:  ";;\n  ;\nSELECT 1;;",
+			want: []string{"SELECT 1"},
+		},
+		{
+			name: "only comments",
+			sql:  "-- nothing here\n-- still nothing;\n",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
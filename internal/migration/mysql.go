@@ -0,0 +1,137 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MySQLDir MySQL 迁移文件的源码目录，相对于项目根目录
+const MySQLDir = "internal/migration/mysql"
+
+//go:embed mysql/*.sql
+var mysqlFS embed.FS
+
+// mysqlLockTimeout 等待其他进程释放迁移锁的最长时间（秒）
+const mysqlLockTimeout = 60
+
+// mysqlTimeLayout schema_migrations.updated_at 的读写格式
+const mysqlTimeLayout = "2006-01-02 15:04:05.000000"
+
+// mysqlDriver 迁移记录保存在 schema_migrations 表中，通过 GET_LOCK 防止并发部署同时执行迁移
+type mysqlDriver struct {
+	db *sql.DB
+}
+
+// NewMySQLMigrator 创建 MySQL 迁移器，迁移文件内嵌在 mysql 目录
+func NewMySQLMigrator(db *gorm.DB, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := Load(mysqlFS, "mysql")
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(&mysqlDriver{db: sqlDB}, migrations, nil, logger), nil
+}
+
+func (d *mysqlDriver) Name() string {
+	return "mysql"
+}
+
+func (d *mysqlDriver) Init(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT UNSIGNED NOT NULL,
+			name       VARCHAR(255)    NOT NULL,
+			checksum   CHAR(64)        NOT NULL,
+			dirty      TINYINT(1)      NOT NULL DEFAULT 0,
+			updated_at DATETIME(6)     NOT NULL,
+			PRIMARY KEY (version)
+		) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4`)
+	if err != nil {
+		return fmt.Errorf("create mysql schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// Lock GET_LOCK 与连接绑定，加锁和释放必须使用同一个连接，锁名包含库名以区分同一实例上的多个库
+func (d *mysqlDriver) Lock(ctx context.Context) (func(), error) {
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire mysql connection for migration lock: %w", err)
+	}
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migrations'), ?)", mysqlLockTimeout).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("acquire mysql migration lock: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("mysql migration lock is held by another process, gave up after %ds", mysqlLockTimeout)
+	}
+
+	return func() {
+		// 使用独立的 context，调用方 context 已取消时仍能释放锁
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn.ExecContext(ctx, "DO RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migrations'))")
+		conn.Close()
+	}, nil
+}
+
+func (d *mysqlDriver) Records(ctx context.Context) ([]Record, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT version, name, checksum, dirty, DATE_FORMAT(updated_at, '%Y-%m-%d %H:%i:%s.%f')
+		FROM schema_migrations
+		ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("read mysql schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		var updatedAt string
+		if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &record.Dirty, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan mysql schema_migrations: %w", err)
+		}
+		// 以文本读写 UTC 时间，不受 DSN 中 parseTime 和 loc 的影响
+		record.UpdatedAt, _ = time.Parse(mysqlTimeLayout, updatedAt)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (d *mysqlDriver) SetRecord(ctx context.Context, record Record) error {
+	_, err := d.db.ExecContext(ctx, `
+		INSERT INTO schema_migrations (version, name, checksum, dirty, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name), checksum = VALUES(checksum), dirty = VALUES(dirty), updated_at = VALUES(updated_at)`,
+		record.Version, record.Name, record.Checksum, record.Dirty, record.UpdatedAt.UTC().Format(mysqlTimeLayout))
+	if err != nil {
+		return fmt.Errorf("write mysql schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (d *mysqlDriver) DeleteRecord(ctx context.Context, version uint64) error {
+	if _, err := d.db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", version); err != nil {
+		return fmt.Errorf("delete mysql schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (d *mysqlDriver) Exec(ctx context.Context, statement string) error {
+	_, err := d.db.ExecContext(ctx, statement)
+	return err
+}
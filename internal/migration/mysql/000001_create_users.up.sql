@@ -0,0 +1,19 @@
+-- 用户表，时间字段为毫秒时间戳，deleted_at = 0 表示未删除
+CREATE TABLE IF NOT EXISTS users (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at    BIGINT          NOT NULL DEFAULT 0,
+    updated_at    BIGINT          NOT NULL DEFAULT 0,
+    deleted_at    BIGINT          NOT NULL DEFAULT 0,
+    username      VARCHAR(64)     NOT NULL,
+    email         VARCHAR(255)    NOT NULL,
+    password      VARCHAR(255)    NOT NULL,
+    name          VARCHAR(100)    NOT NULL DEFAULT '',
+    avatar        VARCHAR(500)    NOT NULL DEFAULT '',
+    phone         VARCHAR(32)     NOT NULL DEFAULT '',
+    status        INT             NOT NULL DEFAULT 1,
+    last_login_at DATETIME(3)     NULL,
+    login_count   INT             NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_users_username (username),
+    UNIQUE KEY idx_users_email (email)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
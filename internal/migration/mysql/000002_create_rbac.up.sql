@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at  BIGINT          NOT NULL DEFAULT 0,
+    updated_at  BIGINT          NOT NULL DEFAULT 0,
+    deleted_at  BIGINT          NOT NULL DEFAULT 0,
+    name        VARCHAR(50)     NOT NULL,
+    description VARCHAR(255)    NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_roles_name (name)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- 权限名形如 resource:action
+CREATE TABLE IF NOT EXISTS permissions (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at  BIGINT          NOT NULL DEFAULT 0,
+    updated_at  BIGINT          NOT NULL DEFAULT 0,
+    deleted_at  BIGINT          NOT NULL DEFAULT 0,
+    name        VARCHAR(100)    NOT NULL,
+    description VARCHAR(255)    NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_permissions_name (name)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       BIGINT UNSIGNED NOT NULL,
+    permission_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    KEY idx_role_permissions_permission_id (permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id BIGINT UNSIGNED NOT NULL,
+    role_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    KEY idx_user_roles_role_id (role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
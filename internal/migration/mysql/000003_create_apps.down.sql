@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS app_keys;
+DROP TABLE IF EXISTS apps;
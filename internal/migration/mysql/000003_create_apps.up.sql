@@ -0,0 +1,30 @@
+-- 事件上报应用
+CREATE TABLE IF NOT EXISTS apps (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at  BIGINT          NOT NULL DEFAULT 0,
+    updated_at  BIGINT          NOT NULL DEFAULT 0,
+    deleted_at  BIGINT          NOT NULL DEFAULT 0,
+    app_id      VARCHAR(36)     NOT NULL,
+    name        VARCHAR(100)    NOT NULL,
+    description VARCHAR(255)    NOT NULL DEFAULT '',
+    status      INT             NOT NULL DEFAULT 1,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_apps_app_id (app_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- 应用的 API Key/Secret，expires_at 为毫秒时间戳，0 表示不过期
+CREATE TABLE IF NOT EXISTS app_keys (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    created_at BIGINT          NOT NULL DEFAULT 0,
+    updated_at BIGINT          NOT NULL DEFAULT 0,
+    deleted_at BIGINT          NOT NULL DEFAULT 0,
+    app_id     VARCHAR(36)     NOT NULL,
+    key_id     VARCHAR(64)     NOT NULL,
+    secret     VARCHAR(128)    NOT NULL,
+    status     INT             NOT NULL DEFAULT 1,
+    expires_at BIGINT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_app_keys_key_id (key_id),
+    KEY idx_app_keys_app_id (app_id),
+    CONSTRAINT fk_app_keys_app FOREIGN KEY (app_id) REFERENCES apps (app_id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,10 @@
+DELETE rp FROM role_permissions rp
+JOIN roles r ON r.id = rp.role_id
+JOIN permissions p ON p.id = rp.permission_id
+WHERE r.name = 'admin'
+  AND p.name IN ('users:read', 'users:write', 'roles:assign', 'apps:manage', 'events:read', 'events:manage');
+
+DELETE FROM permissions
+WHERE name IN ('users:read', 'users:write', 'roles:assign', 'apps:manage', 'events:read', 'events:manage');
+
+DELETE FROM roles WHERE name IN ('admin', 'user');
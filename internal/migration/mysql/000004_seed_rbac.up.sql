@@ -0,0 +1,18 @@
+-- 内置角色和权限，admin 在代码中直接放行全部权限，user 默认不授予任何权限
+INSERT IGNORE INTO roles (name, description, created_at, updated_at) VALUES
+    ('admin', 'Administrator with all permissions', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED)),
+    ('user', 'Regular user', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED));
+
+INSERT IGNORE INTO permissions (name, description, created_at, updated_at) VALUES
+    ('users:read', 'List and view users', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED)),
+    ('users:write', 'Update and delete users', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED)),
+    ('roles:assign', 'Assign and remove user roles', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED)),
+    ('apps:manage', 'Register apps and manage their API keys', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED)),
+    ('events:read', 'Query install events and statistics', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED)),
+    ('events:manage', 'Replay and purge dead-lettered install events', CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED), CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS UNSIGNED));
+
+-- admin 也显式关联全部权限，便于在角色列表中查看
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin'
+  AND p.name IN ('users:read', 'users:write', 'roles:assign', 'apps:manage', 'events:read', 'events:manage');
@@ -0,0 +1,2 @@
+-- 回滚前需先将加密的密钥轮换或解密为明文，否则会截断失败
+ALTER TABLE app_keys MODIFY secret VARCHAR(128) NOT NULL;
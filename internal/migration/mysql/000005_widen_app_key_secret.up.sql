@@ -0,0 +1,2 @@
+-- 加密后的应用密钥（enc:v1: + base64(nonce + 密文 + tag)）超过 128 个字符
+ALTER TABLE app_keys MODIFY secret VARCHAR(255) NOT NULL;
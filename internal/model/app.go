@@ -0,0 +1,67 @@
+package model
+
+// 应用状态
+const (
+	AppStatusDisabled = 0
+	AppStatusActive   = 1
+)
+
+// API Key 状态
+const (
+	AppKeyStatusRevoked = 0
+	AppKeyStatusActive  = 1
+)
+
+// App 事件上报应用注册信息
+type App struct {
+	BaseModel
+	AppID       string   `json:"app_id" gorm:"column:app_id;type:varchar(36);uniqueIndex;not null"`
+	Name        string   `json:"name" gorm:"type:varchar(100);not null"`
+	Description string   `json:"description" gorm:"type:varchar(255)"`
+	Status      int      `json:"status" gorm:"default:1"` // 1:启用 0:禁用
+	Keys        []AppKey `json:"keys,omitempty" gorm:"foreignKey:AppID;references:AppID"`
+}
+
+// AppKey 应用的 API Key/Secret，一个应用可同时拥有多个有效密钥
+type AppKey struct {
+	BaseModel
+	AppID     string `json:"app_id" gorm:"column:app_id;type:varchar(36);index;not null"`
+	KeyID     string `json:"key_id" gorm:"column:key_id;type:varchar(64);uniqueIndex;not null"`
+	Secret    string `json:"-" gorm:"type:varchar(255);not null"` // 配置 app_auth.secret_key 时为 AES-GCM 密文
+	Status    int    `json:"status" gorm:"default:1"`             // 1:有效 0:已吊销
+	ExpiresAt int64  `json:"expires_at"`                          // 毫秒时间戳，0 表示不过期，轮换后旧密钥在宽限期结束时过期
+}
+
+// IsUsable 判断密钥当前是否可用于验签
+func (k *AppKey) IsUsable(nowMilli int64) bool {
+	if k.Status != AppKeyStatusActive {
+		return false
+	}
+	return k.ExpiresAt == 0 || k.ExpiresAt > nowMilli
+}
+
+// AppSignature 上报请求携带的签名信息
+type AppSignature struct {
+	AppID     string
+	KeyID     string
+	Timestamp int64 // Unix 秒
+	Signature string
+}
+
+type CreateAppRequest struct {
+	AppID       string `json:"app_id" validate:"required,max=36"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+type UpdateAppStatusRequest struct {
+	Status *int `json:"status" validate:"required,oneof=0 1"`
+}
+
+// AppKeyResponse 创建或轮换密钥的响应，Secret 只在此时返回一次
+type AppKeyResponse struct {
+	AppID     string `json:"app_id"`
+	KeyID     string `json:"key_id"`
+	Secret    string `json:"secret"`
+	ExpiresAt int64  `json:"expires_at"`
+}
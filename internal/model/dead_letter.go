@@ -0,0 +1,45 @@
+package model
+
+// 死信产生的阶段
+const (
+	DeadLetterStageParse  = "parse"  // 消息无法解析
+	DeadLetterStageInsert = "insert" // 超过重试次数仍无法写入 ClickHouse
+)
+
+// DeadLetterEntry 死信流中的一条记录，Payload 为原始 Stream 消息的全部字段
+type DeadLetterEntry struct {
+	ID         string            `json:"id"`
+	OriginalID string            `json:"original_id"`
+	Stage      string            `json:"stage"`
+	Error      string            `json:"error"`
+	Attempts   int64             `json:"attempts"`
+	FailedAt   int64             `json:"failed_at"` // 毫秒时间戳
+	AppID      string            `json:"app_id"`
+	EventID    string            `json:"event_id"`
+	Payload    map[string]string `json:"payload"`
+}
+
+// DeadLetterListRequest 死信查询，After 为上一页最后一条记录的 ID
+type DeadLetterListRequest struct {
+	After string `form:"after,omitempty"`
+	Size  int64  `form:"size,omitempty"`
+}
+
+// DeadLetterListResponse 死信查询结果
+type DeadLetterListResponse struct {
+	Entries []*DeadLetterEntry `json:"entries"`
+	Total   int64              `json:"total"`
+	NextID  string             `json:"next_id,omitempty"`
+}
+
+// DeadLetterActionRequest 重放或清除死信，All 为 true 时作用于全部记录
+type DeadLetterActionRequest struct {
+	IDs []string `json:"ids"`
+	All bool     `json:"all"`
+}
+
+// DeadLetterActionResponse 重放或清除的记录数，Skipped 为重放全部时没有可重放载荷而保留的记录数
+type DeadLetterActionResponse struct {
+	Affected int64 `json:"affected"`
+	Skipped  int64 `json:"skipped,omitempty"`
+}
@@ -97,7 +97,7 @@ func (InstallEvent) TableName() string {
 
 func (e *InstallEvent) BeforeCreate(tx *gorm.DB) error {
 	if e.EventDate.IsZero() {
-		e.EventDate = e.EventTime.Truncate(24 * time.Hour)
+		e.EventDate = e.GetEventDate()
 	}
 	return nil
 }
@@ -106,8 +106,9 @@ func (e *InstallEvent) IsSuccess() bool {
 	return e.InstallResult == InstallSuccess
 }
 
+// GetEventDate 事件的 UTC 日期，仅用于分区；按调用方时区分桶请使用时间序列查询
 func (e *InstallEvent) GetEventDate() time.Time {
-	return e.EventTime.Truncate(24 * time.Hour)
+	return e.EventTime.UTC().Truncate(24 * time.Hour)
 }
 
 // 请求和响应结构体
@@ -122,7 +123,7 @@ type CreateInstallEventRequest struct {
 	ChannelID        string            `json:"channel_id" validate:"required"`
 	InstallIP        string            `json:"install_ip" validate:"required,ip"`
 	InstallType      InstallType       `json:"install_type" validate:"required,min=1,max=2"`
-	InstallResult    InstallResult     `json:"install_result" validate:"required,min=0,max=1"`
+	InstallResult    InstallResult     `json:"install_result" validate:"min=0,max=1"`
 	OSLanguage       string            `json:"os_language" validate:"required"`
 	OSTimezone       string            `json:"os_timezone" validate:"required"`
 	OSName           string            `json:"os_name" validate:"required"`
@@ -134,8 +135,20 @@ type CreateInstallEventRequest struct {
 	SignatureParams  map[string]string `json:"signature_params"`
 }
 
+// CreateInstallEventBatchRequest 批量上报请求，JSON 请求体格式
+type CreateInstallEventBatchRequest struct {
+	Events []*CreateInstallEventRequest `json:"events"`
+}
+
+// InstallEventBatchResponse 批量上报结果，Duplicates 为去重窗口内已收到而未入队的 event_id
+type InstallEventBatchResponse struct {
+	Accepted   int      `json:"accepted"`
+	Duplicates []string `json:"duplicates,omitempty"`
+}
+
+// InstallEventListRequest 事件查询条件，按 (event_time, event_id) 倒序游标分页，
+// 时间范围为 [start_time, end_time)，首页不传 cursor，后续页传上一页返回的 next_cursor
 type InstallEventListRequest struct {
-	PageRequest
 	AppID         string         `form:"app_id,omitempty"`
 	AppType       *AppType       `form:"app_type,omitempty"`
 	DeviceID      string         `form:"device_id,omitempty"`
@@ -144,6 +157,32 @@ type InstallEventListRequest struct {
 	InstallResult *InstallResult `form:"install_result,omitempty"`
 	StartTime     *time.Time     `form:"start_time,omitempty"`
 	EndTime       *time.Time     `form:"end_time,omitempty"`
+	Size          int            `form:"size,omitempty"`
+	Cursor        string         `form:"cursor,omitempty"`
+}
+
+// GetLimit 每页数量，默认 20，最大 100
+func (r *InstallEventListRequest) GetLimit() int {
+	if r.Size <= 0 {
+		r.Size = 20
+	}
+	if r.Size > 100 {
+		r.Size = 100
+	}
+	return r.Size
+}
+
+// InstallEventCursor 游标位置，即上一页最后一条事件的排序键
+type InstallEventCursor struct {
+	EventTime time.Time
+	EventID   string
+}
+
+// InstallEventListResponse 事件查询结果
+type InstallEventListResponse struct {
+	Events     []*InstallEvent `json:"events"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
 }
 
 type InstallStatsRequest struct {
@@ -174,4 +213,63 @@ type ChannelInstallStats struct {
 type AppTypeInstallStats struct {
 	AppType AppType `json:"app_type"`
 	Count   int64   `json:"count"`
-}
\ No newline at end of file
+}
+// TimeGranularity 时间序列的分桶粒度
+type TimeGranularity string
+
+const (
+	GranularityMinute TimeGranularity = "minute"
+	GranularityHour   TimeGranularity = "hour"
+	GranularityDay    TimeGranularity = "day"
+	GranularityWeek   TimeGranularity = "week"
+)
+
+// 时间序列可选的分组维度
+const (
+	GroupByAppID     = "app_id"
+	GroupByChannelID = "channel_id"
+	GroupByAppType   = "app_type"
+	GroupByOSName    = "os_name"
+)
+
+// InstallTimeSeriesRequest 时间序列查询条件，按 timezone 指定的 IANA 时区分桶，默认 UTC
+type InstallTimeSeriesRequest struct {
+	AppID       string          `form:"app_id,omitempty"`
+	AppType     *AppType        `form:"app_type,omitempty"`
+	ChannelID   string          `form:"channel_id,omitempty"`
+	StartTime   *time.Time      `form:"start_time,omitempty"`
+	EndTime     *time.Time      `form:"end_time,omitempty"`
+	Granularity TimeGranularity `form:"granularity,omitempty"`
+	GroupBy     string          `form:"group_by,omitempty"`
+	Timezone    string          `form:"timezone,omitempty"`
+}
+
+// InstallTimeSeriesRow ClickHouse 返回的单个分桶聚合结果
+type InstallTimeSeriesRow struct {
+	Bucket  time.Time
+	Group   string
+	Total   int64
+	Success int64
+}
+
+// InstallMetricPoint 单个时间桶的指标
+type InstallMetricPoint struct {
+	Time        time.Time `json:"time"`
+	Total       int64     `json:"total"`
+	Success     int64     `json:"success"`
+	SuccessRate float64   `json:"success_rate"`
+}
+
+// InstallTimeSeries 一个分组的时间序列，未分组时 Group 为空
+type InstallTimeSeries struct {
+	Group  string               `json:"group"`
+	Points []InstallMetricPoint `json:"points"`
+}
+
+// InstallTimeSeriesResponse 时间序列查询结果，每个序列包含全部时间桶，无数据的桶补零
+type InstallTimeSeriesResponse struct {
+	Granularity TimeGranularity     `json:"granularity"`
+	GroupBy     string              `json:"group_by,omitempty"`
+	Timezone    string              `json:"timezone"`
+	Series      []InstallTimeSeries `json:"series"`
+}
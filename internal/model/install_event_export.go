@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// 事件导出格式
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatParquet = "parquet"
+)
+
+// 导出任务状态
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// InstallEventExportRequest 导出条件，过滤条件与事件查询相同，忽略 size 和 cursor
+type InstallEventExportRequest struct {
+	InstallEventListRequest
+	Format string `form:"format,omitempty"` // csv、ndjson 或 parquet，默认 csv
+}
+
+// InstallEventExport 异步导出任务
+type InstallEventExport struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Format      string     `json:"format"`
+	UserID      uint64     `json:"user_id"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	FileName    string     `json:"file_name,omitempty"`
+	Bytes       int64      `json:"bytes,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"` // 完成后的下载地址
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"` // 任务状态和文件的过期时间
+	Instance    string     `json:"instance"`   // 执行任务的实例（主机名），导出文件写在该实例的 export.dir 中
+	Process     string     `json:"process"`    // 执行任务的进程，用于启动时识别上次运行遗留的任务
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// 保留策略的执行方式
+const (
+	RetentionModeTTL       = "ttl"
+	RetentionModePartition = "partition"
+)
+
+// 分区归档格式
+const (
+	ArchiveFormatParquet = "parquet"
+	ArchiveFormatNative  = "native"
+)
+
+// RetentionPolicy 保留策略，AppID 为空表示未单独配置的应用
+type RetentionPolicy struct {
+	AppID string `json:"app_id,omitempty"`
+	Days  int    `json:"days"`
+}
+
+// EventPartition install_events 的一个月分区
+type EventPartition struct {
+	ID      string    `json:"id"` // toYYYYMM(event_date)，如 202501
+	MinDate time.Time `json:"min_date"`
+	MaxDate time.Time `json:"max_date"`
+	Rows    uint64    `json:"rows"`
+	Bytes   uint64    `json:"bytes"`
+}
+
+// PartitionSnapshot 分区在某一时刻的状态，归档前后比较以确认归档期间没有写入或变更
+type PartitionSnapshot struct {
+	Rows        uint64 // 查询可见的行数，不含轻量删除的行
+	MaxBlock    int64  // 活跃 part 的最大块号，写入后增加，合并不变
+	DataVersion uint64 // 活跃 part 的最大数据版本，执行删除等变更后增加
+}
+
+// PurgeEventsRequest 按应用删除事件，DeviceID 和 Before 至少指定一个
+type PurgeEventsRequest struct {
+	AppID    string
+	DeviceID string
+	Before   *time.Time // 删除 event_time 早于该时间的事件
+}
@@ -0,0 +1,34 @@
+package model
+
+// 内置角色
+const (
+	RoleAdmin = "admin" // 超级管理员，拥有全部权限
+	RoleUser  = "user"
+)
+
+// 内置权限
+const (
+	PermissionUsersRead    = "users:read"
+	PermissionUsersWrite   = "users:write"
+	PermissionRolesAssign  = "roles:assign"
+	PermissionAppsManage   = "apps:manage"
+	PermissionEventsRead   = "events:read"
+	PermissionEventsManage = "events:manage"
+)
+
+type Role struct {
+	BaseModel
+	Name        string       `json:"name" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string       `json:"description" gorm:"type:varchar(255)"`
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:role_permissions;"`
+}
+
+type Permission struct {
+	BaseModel
+	Name        string `json:"name" gorm:"type:varchar(100);uniqueIndex;not null"` // 形如 resource:action
+	Description string `json:"description" gorm:"type:varchar(255)"`
+}
+
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
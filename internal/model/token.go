@@ -0,0 +1,18 @@
+package model
+
+// RefreshSession 刷新令牌会话: 一个令牌家族对应一次登录，每次刷新在家族内轮换
+type RefreshSession struct {
+	UserID   uint64 `json:"user_id"`
+	Username string `json:"username"`
+	FamilyID string `json:"family_id"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type RefreshTokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+}
@@ -17,6 +17,7 @@ type User struct {
 	Status      int        `json:"status" gorm:"default:1"` // 1:活跃 0:禁用
 	LastLoginAt *time.Time `json:"last_login_at"`
 	LoginCount  int        `json:"login_count" gorm:"default:0"`
+	Roles       []Role     `json:"roles,omitempty" gorm:"many2many:user_roles;"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -49,8 +50,10 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+	User         User   `json:"user"`
 }
 
 type UserListRequest struct {
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"gorm.io/gorm"
+)
+
+type appRepository struct {
+	db *gorm.DB
+}
+
+func NewAppRepository(db *gorm.DB) AppRepository {
+	return &appRepository{db: db}
+}
+
+func (r *appRepository) Create(ctx context.Context, app *model.App) error {
+	if err := r.db.WithContext(ctx).Create(app).Error; err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to create app", err)
+	}
+	return nil
+}
+
+func (r *appRepository) GetByAppID(ctx context.Context, appID string) (*model.App, error) {
+	var app model.App
+	if err := r.db.WithContext(ctx).Preload("Keys").Where("app_id = ?", appID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrAppNotFound
+		}
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to get app", err)
+	}
+	return &app, nil
+}
+
+func (r *appRepository) List(ctx context.Context) ([]*model.App, error) {
+	var apps []*model.App
+	if err := r.db.WithContext(ctx).Order("id").Find(&apps).Error; err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to list apps", err)
+	}
+	return apps, nil
+}
+
+func (r *appRepository) ExistsByAppID(ctx context.Context, appID string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.App{}).Where("app_id = ?", appID).Count(&count).Error; err != nil {
+		return false, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to check app existence", err)
+	}
+	return count > 0, nil
+}
+
+func (r *appRepository) UpdateStatus(ctx context.Context, appID string, status int) error {
+	result := r.db.WithContext(ctx).Model(&model.App{}).Where("app_id = ?", appID).Update("status", status)
+	if result.Error != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to update app status", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errorsx.ErrAppNotFound
+	}
+	return nil
+}
+
+func (r *appRepository) CreateKey(ctx context.Context, key *model.AppKey) error {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to create app key", err)
+	}
+	return nil
+}
+
+func (r *appRepository) GetKey(ctx context.Context, appID, keyID string) (*model.AppKey, error) {
+	var key model.AppKey
+	if err := r.db.WithContext(ctx).Where("app_id = ? AND key_id = ?", appID, keyID).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.New(errorsx.CodeNotFound, "App key not found")
+		}
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to get app key", err)
+	}
+	return &key, nil
+}
+
+func (r *appRepository) ExpireKey(ctx context.Context, keyID string, expiresAt int64) error {
+	if err := r.db.WithContext(ctx).Model(&model.AppKey{}).Where("key_id = ?", keyID).Update("expires_at", expiresAt).Error; err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to expire app key", err)
+	}
+	return nil
+}
+
+func (r *appRepository) RevokeKey(ctx context.Context, keyID string) error {
+	if err := r.db.WithContext(ctx).Model(&model.AppKey{}).Where("key_id = ?", keyID).Update("status", model.AppKeyStatusRevoked).Error; err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to revoke app key", err)
+	}
+	return nil
+}
@@ -2,8 +2,15 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/iswangwenbin/gin-starter/internal/model"
 	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
 )
@@ -11,6 +18,19 @@ import (
 type InstallEventRepository interface {
 	Create(ctx context.Context, event *model.InstallEvent) error
 	CreateBatch(ctx context.Context, events []*model.InstallEvent) error
+	List(ctx context.Context, req *model.InstallEventListRequest, start, end time.Time, after *model.InstallEventCursor, limit int) ([]*model.InstallEvent, error)
+	Stats(ctx context.Context, req *model.InstallStatsRequest, start, end time.Time, topChannels int) (*model.InstallStatsResponse, error)
+	TimeSeries(ctx context.Context, req *model.InstallTimeSeriesRequest, start, end time.Time) ([]*model.InstallTimeSeriesRow, error)
+	Ping(ctx context.Context) error
+	CountEvents(ctx context.Context, req *model.PurgeEventsRequest) (uint64, error)
+	DeleteEvents(ctx context.Context, req *model.PurgeEventsRequest) error
+	ModifyTTL(ctx context.Context, policies []model.RetentionPolicy) error
+	ListPartitions(ctx context.Context) ([]*model.EventPartition, error)
+	PartitionSnapshot(ctx context.Context, id string) (*model.PartitionSnapshot, error)
+	DropPartition(ctx context.Context, id string) error
+	RollupBackfillPartitions(ctx context.Context) ([]string, error)
+	RebuildRollups(ctx context.Context, id string) (time.Time, error)
+	Export(ctx context.Context, req *model.InstallEventListRequest, start, end time.Time, format string, w io.Writer) (int64, error)
 }
 
 type installEventRepository struct {
@@ -21,17 +41,27 @@ func NewInstallEventRepository(ch clickhouse.Conn) InstallEventRepository {
 	return &installEventRepository{ch: ch}
 }
 
+// Ping 检查 ClickHouse 连接是否可用
+func (r *installEventRepository) Ping(ctx context.Context) error {
+	return r.ch.Ping(ctx)
+}
+
 // 单条插入（内部调用批量插入）
 func (r *installEventRepository) Create(ctx context.Context, event *model.InstallEvent) error {
 	return r.CreateBatch(ctx, []*model.InstallEvent{event})
 }
 
-// 批量插入
+// 批量插入，使用由批次内事件生成的去重令牌，重试同一批次时 ClickHouse 不会重复写入
 func (r *installEventRepository) CreateBatch(ctx context.Context, events []*model.InstallEvent) error {
 	if len(events) == 0 {
 		return nil
 	}
 
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+		"insert_deduplicate":         1,
+		"insert_deduplication_token": insertDeduplicationToken(events),
+	}))
+
 	batch, err := r.ch.PrepareBatch(ctx, `
 		INSERT INTO install_events (
 			app_id, app_name, app_version, app_type,
@@ -65,4 +95,312 @@ func (r *installEventRepository) CreateBatch(ctx context.Context, events []*mode
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// insertDeduplicationToken 按 app_id + event_id 排序后计算摘要，批次内容相同则令牌相同，与消费顺序无关
+func insertDeduplicationToken(events []*model.InstallEvent) string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.AppID + ":" + event.EventID
+	}
+	sort.Strings(ids)
+
+	sum := sha256.Sum256([]byte(strings.Join(ids, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// installEventColumns 查询返回的列，与 scanInstallEvent 的顺序一致
+const installEventColumns = `
+	app_id, app_name, app_version, app_type,
+	event_id, event_date, event_time,
+	device_id, channel_id, install_ip,
+	install_type, install_result,
+	os_language, os_timezone, os_name, os_version, os_build, os_family,
+	signature_status, signature_version, signature_params`
+
+// List 按 (event_time, event_id) 倒序查询 [start, end) 内的事件，after 不为空时从游标之后继续；
+// 所有条件均通过参数绑定传入
+func (r *installEventRepository) List(ctx context.Context, req *model.InstallEventListRequest, start, end time.Time, after *model.InstallEventCursor, limit int) ([]*model.InstallEvent, error) {
+	where := listWhere(req, start, end)
+	if after != nil {
+		where.add("(event_time < fromUnixTimestamp64Milli(?, 'UTC') OR (event_time = fromUnixTimestamp64Milli(?, 'UTC') AND event_id < ?))",
+			after.EventTime.UnixMilli(), after.EventTime.UnixMilli(), after.EventID)
+	}
+	args := append(where.args, limit)
+
+	query := "SELECT " + installEventColumns + `
+		FROM install_events
+		WHERE ` + where.String() + `
+		ORDER BY event_time DESC, event_id DESC
+		LIMIT ?`
+
+	rows, err := r.ch.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to query install events", err)
+	}
+	defer rows.Close()
+
+	events := make([]*model.InstallEvent, 0, limit)
+	for rows.Next() {
+		event, err := scanInstallEvent(rows)
+		if err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to scan install event", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read install events", err)
+	}
+
+	return events, nil
+}
+
+// listWhere 事件查询和导出共用的过滤条件
+func listWhere(req *model.InstallEventListRequest, start, end time.Time) *eventWhere {
+	where := newEventWhere(start, end)
+	if req.AppID != "" {
+		where.add("app_id = ?", req.AppID)
+	}
+	if req.AppType != nil {
+		where.add("app_type = ?", uint8(*req.AppType))
+	}
+	if req.DeviceID != "" {
+		where.add("device_id = ?", req.DeviceID)
+	}
+	if req.ChannelID != "" {
+		where.add("channel_id = ?", req.ChannelID)
+	}
+	if req.InstallType != nil {
+		where.add("install_type = ?", uint8(*req.InstallType))
+	}
+	if req.InstallResult != nil {
+		where.add("install_result = ?", uint8(*req.InstallResult))
+	}
+	return where
+}
+
+// Stats 统计 [start, end) 内的安装汇总、渠道排行和平台分布，整小时、整天的部分读取汇总表，
+// 去重设备数合并各时间段的 uniqCombined 状态
+func (r *installEventRepository) Stats(ctx context.Context, req *model.InstallStatsRequest, start, end time.Time, topChannels int) (*model.InstallStatsResponse, error) {
+	segments := planSegments(start, end, sourceDaily)
+	filters := eventFilters{appID: req.AppID, appType: req.AppType, channelID: req.ChannelID}
+	dims := []segmentDim{
+		{expr: "channel_id", alias: "channel_id"},
+		{expr: "app_type", alias: "app_type"},
+	}
+	union, args := unionSegments(segments, dims, filters, true)
+
+	stats := &model.InstallStatsResponse{
+		TopChannels:      []model.ChannelInstallStats{},
+		AppTypeBreakdown: []model.AppTypeInstallStats{},
+	}
+
+	var total, success, first, repeat, devices uint64
+	err := r.ch.QueryRow(ctx, `
+		SELECT
+			sum(total),
+			sum(success),
+			sum(first_count),
+			sum(repeat_count),
+			uniqCombinedMerge(device_state)
+		FROM (`+union+`
+		)`,
+		args...,
+	).Scan(&total, &success, &first, &repeat, &devices)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to query install stats", err)
+	}
+
+	stats.TotalEvents = int64(total)
+	stats.SuccessEvents = int64(success)
+	stats.FailedEvents = int64(total - success)
+	stats.FirstInstalls = int64(first)
+	stats.RepeatInstalls = int64(repeat)
+	stats.UniqueDevices = int64(devices)
+	if total > 0 {
+		stats.SuccessRate = float64(success) / float64(total)
+	}
+
+	union, args = unionSegments(segments, dims, filters, false)
+	rows, err := r.ch.Query(ctx, `
+		SELECT channel_id, sum(total) AS cnt
+		FROM (`+union+`
+		)
+		GROUP BY channel_id
+		ORDER BY cnt DESC, channel_id
+		LIMIT ?`,
+		append(args, topChannels)...,
+	)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to query channel stats", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.ChannelInstallStats
+		var count uint64
+		if err := rows.Scan(&item.ChannelID, &count); err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to scan channel stats", err)
+		}
+		item.Count = int64(count)
+		stats.TopChannels = append(stats.TopChannels, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read channel stats", err)
+	}
+
+	union, args = unionSegments(segments, dims, filters, false)
+	typeRows, err := r.ch.Query(ctx, `
+		SELECT app_type, sum(total)
+		FROM (`+union+`
+		)
+		GROUP BY app_type
+		ORDER BY app_type`,
+		args...,
+	)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to query app type stats", err)
+	}
+	defer typeRows.Close()
+
+	for typeRows.Next() {
+		var appType uint8
+		var count uint64
+		if err := typeRows.Scan(&appType, &count); err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to scan app type stats", err)
+		}
+		stats.AppTypeBreakdown = append(stats.AppTypeBreakdown, model.AppTypeInstallStats{
+			AppType: model.AppType(appType),
+			Count:   int64(count),
+		})
+	}
+	if err := typeRows.Err(); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read app type stats", err)
+	}
+
+	return stats, nil
+}
+
+// timeBucketExprs 各粒度在指定时区下的分桶表达式，结果为桶起点的 Unix 秒，{time} 为来源的时间列
+var timeBucketExprs = map[model.TimeGranularity]string{
+	model.GranularityMinute: "toUnixTimestamp(toStartOfMinute({time}, ?))",
+	model.GranularityHour:   "toUnixTimestamp(toStartOfHour({time}, ?))",
+	model.GranularityDay:    "toUnixTimestamp(toStartOfDay({time}, ?))",
+	model.GranularityWeek:   "toUnixTimestamp(toDateTime(toMonday({time}, ?), ?))",
+}
+
+// groupByExprs 可分组的维度，列名只能来自此白名单；os_name 不在汇总表中，按其分组时只读明细
+var groupByExprs = map[string]string{
+	model.GroupByAppID:     "app_id",
+	model.GroupByChannelID: "channel_id",
+	model.GroupByAppType:   "toString(app_type)",
+	model.GroupByOSName:    "os_name",
+}
+
+// TimeSeries 按粒度和时区分桶统计 [start, end) 内的安装数与成功数，只返回有数据的桶；
+// 粒度和时区允许时整小时、整天的部分读取汇总表
+func (r *installEventRepository) TimeSeries(ctx context.Context, req *model.InstallTimeSeriesRequest, start, end time.Time) ([]*model.InstallTimeSeriesRow, error) {
+	bucketExpr, ok := timeBucketExprs[req.Granularity]
+	if !ok {
+		return nil, errorsx.New(errorsx.CodeBadRequest, "Unsupported granularity")
+	}
+	var bucketArgs []interface{}
+	for i := strings.Count(bucketExpr, "?"); i > 0; i-- {
+		bucketArgs = append(bucketArgs, req.Timezone)
+	}
+
+	groupExpr := "''"
+	if req.GroupBy != "" {
+		if groupExpr, ok = groupByExprs[req.GroupBy]; !ok {
+			return nil, errorsx.New(errorsx.CodeBadRequest, "Unsupported group_by dimension")
+		}
+	}
+
+	segments := planSegments(start, end, timeSeriesSource(req, start, end))
+	filters := eventFilters{appID: req.AppID, appType: req.AppType, channelID: req.ChannelID}
+	dims := []segmentDim{
+		{expr: bucketExpr, alias: "bucket", args: bucketArgs},
+		{expr: groupExpr, alias: "grp"},
+	}
+	union, args := unionSegments(segments, dims, filters, false)
+
+	rows, err := r.ch.Query(ctx, `
+		SELECT bucket, grp, sum(total), sum(success)
+		FROM (`+union+`
+		)
+		GROUP BY bucket, grp
+		ORDER BY bucket, grp`,
+		args...,
+	)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to query install time series", err)
+	}
+	defer rows.Close()
+
+	var result []*model.InstallTimeSeriesRow
+	for rows.Next() {
+		var bucket uint32
+		var total, success uint64
+		row := &model.InstallTimeSeriesRow{}
+		if err := rows.Scan(&bucket, &row.Group, &total, &success); err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to scan install time series", err)
+		}
+		row.Bucket = time.Unix(int64(bucket), 0)
+		row.Total = int64(total)
+		row.Success = int64(success)
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read install time series", err)
+	}
+
+	return result, nil
+}
+
+func scanInstallEvent(rows driver.Rows) (*model.InstallEvent, error) {
+	var (
+		event                               model.InstallEvent
+		appType, installType, installResult uint8
+	)
+	err := rows.Scan(
+		&event.AppID, &event.AppName, &event.AppVersion, &appType,
+		&event.EventID, &event.EventDate, &event.EventTime,
+		&event.DeviceID, &event.ChannelID, &event.InstallIP,
+		&installType, &installResult,
+		&event.OSLanguage, &event.OSTimezone, &event.OSName, &event.OSVersion, &event.OSBuild, &event.OSFamily,
+		&event.SignatureStatus, &event.SignatureVersion, &event.SignatureParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.AppType = model.AppType(appType)
+	event.InstallType = model.InstallType(installType)
+	event.InstallResult = model.InstallResult(installResult)
+	return &event, nil
+}
+
+// eventWhere 参数化的 WHERE 条件
+type eventWhere struct {
+	conditions []string
+	args       []interface{}
+}
+
+// newEventWhere 以时间范围 [start, end) 开始构建条件，event_date 条件用于分区裁剪
+func newEventWhere(start, end time.Time) *eventWhere {
+	w := &eventWhere{}
+	w.add("event_date >= toDate(fromUnixTimestamp64Milli(?, 'UTC'))", start.UnixMilli())
+	w.add("event_date <= toDate(fromUnixTimestamp64Milli(?, 'UTC'))", end.UnixMilli())
+	w.add("event_time >= fromUnixTimestamp64Milli(?, 'UTC')", start.UnixMilli())
+	w.add("event_time < fromUnixTimestamp64Milli(?, 'UTC')", end.UnixMilli())
+	return w
+}
+
+func (w *eventWhere) add(condition string, args ...interface{}) {
+	w.conditions = append(w.conditions, condition)
+	w.args = append(w.args, args...)
+}
+
+func (w *eventWhere) String() string {
+	return strings.Join(w.conditions, " AND ")
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/clickhousex"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+)
+
+// exportFormats 导出格式对应的 ClickHouse 输出格式和设置，时间以 ISO 8601（UTC）输出
+var exportFormats = map[string]clickhousex.ExportRequest{
+	model.ExportFormatCSV: {
+		Format:   "CSVWithNames",
+		Settings: map[string]string{"date_time_output_format": "iso"},
+	},
+	model.ExportFormatNDJSON: {
+		Format:   "JSONEachRow",
+		Settings: map[string]string{"date_time_output_format": "iso"},
+	},
+	model.ExportFormatParquet: {
+		Format:   "Parquet",
+		Settings: map[string]string{"output_format_parquet_compression_method": "zstd"},
+	},
+}
+
+// Export 按 (event_time, event_id) 顺序导出 [start, end) 内的事件，过滤条件与 List 相同；
+// 结果由 ClickHouse 通过 HTTP 接口编码为指定格式后流式写入 w，不在内存中缓存，返回写入的字节数
+func (r *installEventRepository) Export(ctx context.Context, req *model.InstallEventListRequest, start, end time.Time, format string, w io.Writer) (int64, error) {
+	export, ok := exportFormats[format]
+	if !ok {
+		return 0, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Unsupported export format %q", format))
+	}
+
+	where, params, err := listWhere(req, start, end).httpParams()
+	if err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to build export query", err)
+	}
+	export.Query = "SELECT " + installEventColumns + `
+		FROM install_events
+		WHERE ` + where + `
+		ORDER BY event_time, event_id`
+	export.Params = params
+
+	result, err := clickhousex.Export(ctx, export, w)
+	if err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to export install events", err)
+	}
+	return result.Bytes, nil
+}
+
+// httpParams 将条件中的 ? 依次替换为 {pN:Type} 服务端参数，用于不支持客户端绑定的 HTTP 接口
+func (w *eventWhere) httpParams() (string, map[string]string, error) {
+	params := make(map[string]string, len(w.args))
+	var b strings.Builder
+	i := 0
+	for _, r := range w.String() {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		if i >= len(w.args) {
+			return "", nil, fmt.Errorf("missing argument for placeholder %d", i)
+		}
+
+		name := fmt.Sprintf("p%d", i)
+		var typ string
+		switch arg := w.args[i].(type) {
+		case string:
+			// 参数值按 Escaped 格式解析
+			typ, params[name] = "String", strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`).Replace(arg)
+		case int64:
+			typ, params[name] = "Int64", fmt.Sprint(arg)
+		case uint8:
+			typ, params[name] = "UInt8", fmt.Sprint(arg)
+		default:
+			return "", nil, fmt.Errorf("unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(&b, "{%s:%s}", name, typ)
+		i++
+	}
+	if i != len(w.args) {
+		return "", nil, fmt.Errorf("%d arguments for %d placeholders", len(w.args), i)
+	}
+	return b.String(), params, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHTTPParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		where      *eventWhere
+		wantWhere  string
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "typed placeholders",
+			where:      &eventWhere{conditions: []string{"app_id = ?", "event_time >= fromUnixTimestamp64Milli(?, 'UTC')", "is_first = ?"}, args: []interface{}{"app_1", int64(1735689600000), uint8(1)}},
+			wantWhere:  "app_id = {p0:String} AND event_time >= fromUnixTimestamp64Milli({p1:Int64}, 'UTC') AND is_first = {p2:UInt8}",
+			wantParams: map[string]string{"p0": "app_1", "p1": "1735689600000", "p2": "1"},
+		},
+		{
+			name:       "string escaped for server binding",
+			where:      &eventWhere{conditions: []string{"device_id = ?"}, args: []interface{}{"a\\b\tc\nd'e"}},
+			wantWhere:  "device_id = {p0:String}",
+			wantParams: map[string]string{"p0": `a\\b\tc\nd'e`},
+		},
+		{
+			name:       "no conditions",
+			where:      &eventWhere{},
+			wantWhere:  "",
+			wantParams: map[string]string{},
+		},
+		{
+			name:    "missing argument",
+			where:   &eventWhere{conditions: []string{"app_id = ?", "device_id = ?"}, args: []interface{}{"app_1"}},
+			wantErr: true,
+		},
+		{
+			name:    "extra argument",
+			where:   &eventWhere{conditions: []string{"app_id = ?"}, args: []interface{}{"app_1", "extra"}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			where:   &eventWhere{conditions: []string{"ratio = ?"}, args: []interface{}{1.5}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, params, err := tt.where.httpParams()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("httpParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if where != tt.wantWhere {
+				t.Errorf("httpParams() where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("httpParams() params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+)
+
+// CountEvents 统计待删除的事件数
+func (r *installEventRepository) CountEvents(ctx context.Context, req *model.PurgeEventsRequest) (uint64, error) {
+	where := purgeWhere(req)
+
+	var count uint64
+	if err := r.ch.QueryRow(ctx, "SELECT count() FROM install_events WHERE "+where.String(), where.args...).Scan(&count); err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to count install events", err)
+	}
+	return count, nil
+}
+
+// DeleteEvents 轻量删除：行立即对查询不可见，数据在后台合并时清除
+func (r *installEventRepository) DeleteEvents(ctx context.Context, req *model.PurgeEventsRequest) error {
+	where := purgeWhere(req)
+	if err := r.ch.Exec(ctx, "DELETE FROM install_events WHERE "+where.String(), where.args...); err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to delete install events", err)
+	}
+	return nil
+}
+
+func purgeWhere(req *model.PurgeEventsRequest) *eventWhere {
+	where := &eventWhere{}
+	where.add("app_id = ?", req.AppID)
+	if req.DeviceID != "" {
+		where.add("device_id = ?", req.DeviceID)
+	}
+	if req.Before != nil {
+		where.add("event_date <= toDate(fromUnixTimestamp64Milli(?, 'UTC'))", req.Before.UnixMilli())
+		where.add("event_time < fromUnixTimestamp64Milli(?, 'UTC')", req.Before.UnixMilli())
+	}
+	return where
+}
+
+// ModifyTTL 以保留策略替换 install_events 的 TTL，策略为空时移除 TTL
+// AppID 为空的默认策略只作用于没有单独策略的应用
+func (r *installEventRepository) ModifyTTL(ctx context.Context, policies []model.RetentionPolicy) error {
+	var rules, appIDs []string
+	var defaultPolicy *model.RetentionPolicy
+	for i, policy := range policies {
+		if policy.AppID == "" {
+			defaultPolicy = &policies[i]
+			continue
+		}
+		appIDs = append(appIDs, quoteString(policy.AppID))
+		rules = append(rules, fmt.Sprintf("event_date + INTERVAL %d DAY DELETE WHERE app_id = %s", policy.Days, quoteString(policy.AppID)))
+	}
+	if defaultPolicy != nil {
+		rule := fmt.Sprintf("event_date + INTERVAL %d DAY DELETE", defaultPolicy.Days)
+		if len(appIDs) > 0 {
+			rule += " WHERE app_id NOT IN (" + strings.Join(appIDs, ", ") + ")"
+		}
+		rules = append(rules, rule)
+	}
+
+	query := "ALTER TABLE install_events MODIFY TTL " + strings.Join(rules, ", ")
+	if len(rules) == 0 {
+		// 表没有 TTL 时 REMOVE TTL 会报错
+		var engine string
+		if err := r.ch.QueryRow(ctx, "SELECT engine_full FROM system.tables WHERE database = currentDatabase() AND name = 'install_events'").Scan(&engine); err != nil {
+			return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read install events TTL", err)
+		}
+		if !strings.Contains(engine, " TTL ") {
+			return nil
+		}
+		query = "ALTER TABLE install_events REMOVE TTL"
+	}
+	if err := r.ch.Exec(ctx, query); err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to modify install events TTL", err)
+	}
+	return nil
+}
+
+// ListPartitions 列出 install_events 的活跃分区，按分区 ID 排序
+func (r *installEventRepository) ListPartitions(ctx context.Context) ([]*model.EventPartition, error) {
+	rows, err := r.ch.Query(ctx, `
+		SELECT partition_id, min(min_date), max(max_date), sum(rows), sum(bytes_on_disk)
+		FROM system.parts
+		WHERE database = currentDatabase() AND table = 'install_events' AND active
+		GROUP BY partition_id
+		ORDER BY partition_id`)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to list install event partitions", err)
+	}
+	defer rows.Close()
+
+	var partitions []*model.EventPartition
+	for rows.Next() {
+		partition := &model.EventPartition{}
+		if err := rows.Scan(&partition.ID, &partition.MinDate, &partition.MaxDate, &partition.Rows, &partition.Bytes); err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to scan install event partition", err)
+		}
+		partitions = append(partitions, partition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read install event partitions", err)
+	}
+	return partitions, nil
+}
+
+// PartitionSnapshot 读取分区活跃 part 的最大块号、数据版本和可见行数，先读 part 信息，期间的写入会使块号变化
+func (r *installEventRepository) PartitionSnapshot(ctx context.Context, id string) (*model.PartitionSnapshot, error) {
+	snapshot := &model.PartitionSnapshot{}
+	if err := r.ch.QueryRow(ctx, `
+		SELECT max(max_block_number), max(data_version)
+		FROM system.parts
+		WHERE database = currentDatabase() AND table = 'install_events' AND active AND partition_id = ?`, id,
+	).Scan(&snapshot.MaxBlock, &snapshot.DataVersion); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read install event partition parts", err)
+	}
+	if err := r.ch.QueryRow(ctx, "SELECT count() FROM install_events WHERE _partition_id = ?", id).Scan(&snapshot.Rows); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to count install event partition rows", err)
+	}
+	return snapshot, nil
+}
+
+// DropPartition 删除一个月分区，id 由调用方校验为 YYYYMM
+func (r *installEventRepository) DropPartition(ctx context.Context, id string) error {
+	if err := r.ch.Exec(ctx, "ALTER TABLE install_events DROP PARTITION ID ?", id); err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to drop install event partition", err)
+	}
+	return nil
+}
+
+// quoteString 转义为 ClickHouse 字符串字面量，用于不支持参数绑定的 DDL
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+)
+
+// eventSource 统计查询的数据来源，值越大粒度越粗
+type eventSource int
+
+const (
+	sourceRaw    eventSource = iota // install_events 明细
+	sourceHourly                    // install_events_hourly，按 UTC 小时汇总
+	sourceDaily                     // install_events_daily，按 UTC 日期汇总
+)
+
+// eventSegment 一个时间段 [start, end) 及读取它的数据来源
+type eventSegment struct {
+	source eventSource
+	start  time.Time
+	end    time.Time
+}
+
+// segmentDim 子查询中的分组列，expr 中的 {time} 替换为来源的时间列
+type segmentDim struct {
+	expr  string
+	alias string
+	args  []interface{}
+}
+
+// planSegments 将 [start, end) 拆分为尽量粗的时间段：整天读天表，整小时读小时表，只有首尾不足一小时的部分读明细；
+// coarsest 为允许使用的最粗来源
+func planSegments(start, end time.Time, coarsest eventSource) []eventSegment {
+	var segments []eventSegment
+	add := func(source eventSource, from, to time.Time) {
+		if from.Before(to) {
+			segments = append(segments, eventSegment{source: source, start: from, end: to})
+		}
+	}
+
+	hourStart, hourEnd := ceilTime(start, time.Hour), end.UTC().Truncate(time.Hour)
+	if coarsest == sourceRaw || !hourStart.Before(hourEnd) {
+		add(sourceRaw, start, end)
+	} else {
+		add(sourceRaw, start, hourStart)
+		dayStart, dayEnd := ceilTime(hourStart, 24*time.Hour), hourEnd.Truncate(24*time.Hour)
+		if coarsest == sourceDaily && dayStart.Before(dayEnd) {
+			add(sourceHourly, hourStart, dayStart)
+			add(sourceDaily, dayStart, dayEnd)
+			add(sourceHourly, dayEnd, hourEnd)
+		} else {
+			add(sourceHourly, hourStart, hourEnd)
+		}
+		add(sourceRaw, hourEnd, end)
+	}
+
+	// 空区间仍返回一个明细段，保证查询语句有效
+	if len(segments) == 0 {
+		segments = append(segments, eventSegment{source: sourceRaw, start: start, end: end})
+	}
+	return segments
+}
+
+// ceilTime 向上取整到 d 的整数倍（UTC）
+func ceilTime(t time.Time, d time.Duration) time.Time {
+	t = t.UTC()
+	truncated := t.Truncate(d)
+	if truncated.Before(t) {
+		truncated = truncated.Add(d)
+	}
+	return truncated
+}
+
+// timeSeriesSource 按粒度、分组维度和时区确定时间序列可用的最粗来源：
+// 分钟粒度和按 os_name 分组只能读明细；小时表要求时区偏移为整小时；天表按 UTC 日期汇总，只在偏移始终为 0 时用于天、周粒度
+func timeSeriesSource(req *model.InstallTimeSeriesRequest, start, end time.Time) eventSource {
+	if req.Granularity == model.GranularityMinute || req.GroupBy == model.GroupByOSName {
+		return sourceRaw
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return sourceRaw
+	}
+
+	utc := true
+	for _, offset := range zoneOffsets(loc, start, end) {
+		if offset%3600 != 0 {
+			return sourceRaw
+		}
+		if offset != 0 {
+			utc = false
+		}
+	}
+	if req.Granularity == model.GranularityHour || !utc {
+		return sourceHourly
+	}
+	return sourceDaily
+}
+
+// zoneOffsets 时区在区间首尾及所跨年份冬夏两季的 UTC 偏移（秒），用于判断夏令时等偏移变化
+func zoneOffsets(loc *time.Location, start, end time.Time) []int {
+	var offsets []int
+	for _, t := range []time.Time{start, end} {
+		_, offset := t.In(loc).Zone()
+		offsets = append(offsets, offset)
+	}
+	for year := start.Year(); year <= end.Year(); year++ {
+		for _, month := range []time.Month{time.January, time.July} {
+			_, offset := time.Date(year, month, 1, 0, 0, 0, 0, loc).Zone()
+			offsets = append(offsets, offset)
+		}
+	}
+	return offsets
+}
+
+// eventFilters 统计查询的过滤条件，对应的列在明细和汇总表中同名
+type eventFilters struct {
+	appID     string
+	appType   *model.AppType
+	channelID string
+}
+
+func (f eventFilters) apply(w *eventWhere) {
+	if f.appID != "" {
+		w.add("app_id = ?", f.appID)
+	}
+	if f.appType != nil {
+		w.add("app_type = ?", uint8(*f.appType))
+	}
+	if f.channelID != "" {
+		w.add("channel_id = ?", f.channelID)
+	}
+}
+
+// unionSegments 将各时间段的预聚合子查询以 UNION ALL 拼接，各子查询返回 dims 的别名列以及
+// total、success、first_count、repeat_count 和 withDevices 时的 device_state（uniqCombined 状态）
+func unionSegments(segments []eventSegment, dims []segmentDim, filters eventFilters, withDevices bool) (string, []interface{}) {
+	var queries []string
+	var args []interface{}
+	for _, segment := range segments {
+		query, segmentArgs := segmentQuery(segment, dims, filters, withDevices)
+		queries = append(queries, query)
+		args = append(args, segmentArgs...)
+	}
+	return strings.Join(queries, "\n\t\tUNION ALL"), args
+}
+
+func segmentQuery(segment eventSegment, dims []segmentDim, filters eventFilters, withDevices bool) (string, []interface{}) {
+	var table, timeColumn string
+	where := &eventWhere{}
+	switch segment.source {
+	case sourceHourly:
+		table, timeColumn = "install_events_hourly", "hour"
+		where.add("hour >= toDateTime(?, 'UTC')", segment.start.Unix())
+		where.add("hour < toDateTime(?, 'UTC')", segment.end.Unix())
+	case sourceDaily:
+		table, timeColumn = "install_events_daily", "toDateTime(day, 'UTC')"
+		where.add("day >= toDate(toDateTime(?, 'UTC'))", segment.start.Unix())
+		where.add("day < toDate(toDateTime(?, 'UTC'))", segment.end.Unix())
+	default:
+		table, timeColumn = "install_events", "event_time"
+		where = newEventWhere(segment.start, segment.end)
+	}
+
+	// 别名与汇总表的列名不同，避免 ClickHouse 将聚合函数参数解析为同名别名
+	var measures []string
+	if segment.source == sourceRaw {
+		measures = []string{
+			"count() AS total",
+			fmt.Sprintf("countIf(install_result = %d) AS success", uint8(model.InstallSuccess)),
+			fmt.Sprintf("countIf(install_type = %d) AS first_count", uint8(model.FirstInstall)),
+			fmt.Sprintf("countIf(install_type = %d) AS repeat_count", uint8(model.RepeatInstall)),
+		}
+		if withDevices {
+			measures = append(measures, "uniqCombinedState(device_id) AS device_state")
+		}
+	} else {
+		measures = []string{
+			"sum(events) AS total",
+			fmt.Sprintf("sumIf(events, install_result = %d) AS success", uint8(model.InstallSuccess)),
+			"sum(first_installs) AS first_count",
+			"sum(repeat_installs) AS repeat_count",
+		}
+		if withDevices {
+			measures = append(measures, "uniqCombinedMergeState(devices) AS device_state")
+		}
+	}
+	filters.apply(where)
+
+	var columns, aliases []string
+	var args []interface{}
+	for _, dim := range dims {
+		columns = append(columns, strings.ReplaceAll(dim.expr, "{time}", timeColumn)+" AS "+dim.alias)
+		aliases = append(aliases, dim.alias)
+		args = append(args, dim.args...)
+	}
+	columns = append(columns, measures...)
+	args = append(args, where.args...)
+
+	query := `
+		SELECT ` + strings.Join(columns, ", ") + `
+		FROM ` + table + `
+		WHERE ` + where.String()
+	if len(aliases) > 0 {
+		query += `
+		GROUP BY ` + strings.Join(aliases, ", ")
+	}
+	return query, args
+}
+
+// rollupRebuildQueries 与物化视图相同的聚合和去重规则（每个排序键保留 inserted_at 最大的一行），
+// 读取一个分区在 (from, to] 之间写入的明细，写入临时表
+var rollupRebuildQueries = []string{`
+	INSERT INTO install_events_hourly_rebuild
+	SELECT
+		app_id, channel_id, app_type, install_result,
+		toStartOfHour(toDateTime(event_time, 'UTC')) AS hour,
+		count(), countIf(install_type = 1), countIf(install_type = 2), uniqCombinedState(device_id)
+	FROM
+	(
+		SELECT app_id, channel_id, app_type, install_result, install_type, event_time, device_id
+		FROM install_events
+		WHERE _partition_id = ?
+			AND inserted_at > fromUnixTimestamp64Milli(?, 'UTC') AND inserted_at <= fromUnixTimestamp64Milli(?, 'UTC')
+		ORDER BY inserted_at DESC
+		LIMIT 1 BY app_id, event_date, event_time, event_id
+	)
+	GROUP BY app_id, channel_id, app_type, install_result, hour`, `
+	INSERT INTO install_events_daily_rebuild
+	SELECT
+		app_id, channel_id, app_type, install_result,
+		toDate(event_time, 'UTC') AS day,
+		count(), countIf(install_type = 1), countIf(install_type = 2), uniqCombinedState(device_id)
+	FROM
+	(
+		SELECT app_id, channel_id, app_type, install_result, install_type, event_time, device_id
+		FROM install_events
+		WHERE _partition_id = ?
+			AND inserted_at > fromUnixTimestamp64Milli(?, 'UTC') AND inserted_at <= fromUnixTimestamp64Milli(?, 'UTC')
+		ORDER BY inserted_at DESC
+		LIMIT 1 BY app_id, event_date, event_time, event_id
+	)
+	GROUP BY app_id, channel_id, app_type, install_result, day`,
+}
+
+// rollupTables 汇总表及重建时使用的临时表
+var rollupTables = []struct{ table, rebuild string }{
+	{"install_events_hourly", "install_events_hourly_rebuild"},
+	{"install_events_daily", "install_events_daily_rebuild"},
+}
+
+// RollupBackfillPartitions 含有物化视图创建之前写入的明细、需要回填汇总的分区
+func (r *installEventRepository) RollupBackfillPartitions(ctx context.Context) ([]string, error) {
+	rows, err := r.ch.Query(ctx, `
+		SELECT DISTINCT _partition_id
+		FROM install_events
+		WHERE inserted_at <= (SELECT max(cutoff) FROM install_events_rollup_cutoff)
+		ORDER BY _partition_id`)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to list rollup backfill partitions", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to scan rollup backfill partition", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read rollup backfill partitions", err)
+	}
+	return ids, nil
+}
+
+// RebuildRollups 以去重后的明细重建一个月分区的小时、天级汇总，可重复执行：先记录截止时间，把截止时间及之前写入的明细
+// 聚合到临时表，再补入聚合期间写入的明细，最后以 REPLACE PARTITION 原子替换汇总表中的该分区；返回补齐到的时间点
+func (r *installEventRepository) RebuildRollups(ctx context.Context, id string) (time.Time, error) {
+	// 清理上次中断留下的临时数据
+	if err := r.dropRebuildPartition(ctx, id); err != nil {
+		return time.Time{}, err
+	}
+
+	cutoff, err := r.now64(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := r.aggregateRollups(ctx, id, time.UnixMilli(0), cutoff); err != nil {
+		return time.Time{}, err
+	}
+
+	// 聚合期间写入的明细已由物化视图计入汇总表，替换前按相同规则补入临时表
+	caughtUp, err := r.now64(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := r.aggregateRollups(ctx, id, cutoff, caughtUp); err != nil {
+		return time.Time{}, err
+	}
+
+	for _, t := range rollupTables {
+		if err := r.ch.Exec(ctx, "ALTER TABLE "+t.table+" REPLACE PARTITION ID ? FROM "+t.rebuild, id); err != nil {
+			return time.Time{}, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to replace install event rollups", err)
+		}
+	}
+	if err := r.dropRebuildPartition(ctx, id); err != nil {
+		return time.Time{}, err
+	}
+	return caughtUp, nil
+}
+
+func (r *installEventRepository) now64(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	if err := r.ch.QueryRow(ctx, "SELECT now64(3, 'UTC')").Scan(&now); err != nil {
+		return time.Time{}, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to read rollup cutoff", err)
+	}
+	return now, nil
+}
+
+func (r *installEventRepository) aggregateRollups(ctx context.Context, id string, from, to time.Time) error {
+	for _, query := range rollupRebuildQueries {
+		if err := r.ch.Exec(ctx, query, id, from.UnixMilli(), to.UnixMilli()); err != nil {
+			return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to backfill install event rollups", err)
+		}
+	}
+	return nil
+}
+
+func (r *installEventRepository) dropRebuildPartition(ctx context.Context, id string) error {
+	for _, t := range rollupTables {
+		if err := r.ch.Exec(ctx, "ALTER TABLE "+t.rebuild+" DROP PARTITION ID ?", id); err != nil {
+			return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to clear install event rollup staging", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+)
+
+func TestCeilTime(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		name string
+		t    time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{name: "aligned hour", t: utc(2025, 1, 1, 10, 0), d: time.Hour, want: utc(2025, 1, 1, 10, 0)},
+		{name: "rounds up to next hour", t: utc(2025, 1, 1, 10, 1), d: time.Hour, want: utc(2025, 1, 1, 11, 0)},
+		{name: "rounds up sub-second", t: utc(2025, 1, 1, 10, 0).Add(time.Millisecond), d: time.Hour, want: utc(2025, 1, 1, 11, 0)},
+		{name: "day in UTC regardless of zone", t: time.Date(2025, 1, 2, 7, 0, 0, 0, shanghai), d: 24 * time.Hour, want: utc(2025, 1, 2, 0, 0)},
+		{name: "aligned day", t: utc(2025, 1, 2, 0, 0), d: 24 * time.Hour, want: utc(2025, 1, 2, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ceilTime(tt.t, tt.d)
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("ceilTime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanSegments(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end time.Time
+		coarsest   eventSource
+		want       []eventSegment
+	}{
+		{
+			name:     "raw only",
+			start:    utc(2025, 1, 1, 0, 0),
+			end:      utc(2025, 1, 3, 0, 0),
+			coarsest: sourceRaw,
+			want:     []eventSegment{{sourceRaw, utc(2025, 1, 1, 0, 0), utc(2025, 1, 3, 0, 0)}},
+		},
+		{
+			name:     "within one hour reads raw",
+			start:    utc(2025, 1, 1, 10, 5),
+			end:      utc(2025, 1, 1, 10, 50),
+			coarsest: sourceDaily,
+			want:     []eventSegment{{sourceRaw, utc(2025, 1, 1, 10, 5), utc(2025, 1, 1, 10, 50)}},
+		},
+		{
+			name:     "partial hours around hourly",
+			start:    utc(2025, 1, 1, 10, 30),
+			end:      utc(2025, 1, 1, 13, 15),
+			coarsest: sourceDaily,
+			want: []eventSegment{
+				{sourceRaw, utc(2025, 1, 1, 10, 30), utc(2025, 1, 1, 11, 0)},
+				{sourceHourly, utc(2025, 1, 1, 11, 0), utc(2025, 1, 1, 13, 0)},
+				{sourceRaw, utc(2025, 1, 1, 13, 0), utc(2025, 1, 1, 13, 15)},
+			},
+		},
+		{
+			name:     "whole days read daily",
+			start:    utc(2025, 1, 1, 22, 30),
+			end:      utc(2025, 1, 4, 2, 0),
+			coarsest: sourceDaily,
+			want: []eventSegment{
+				{sourceRaw, utc(2025, 1, 1, 22, 30), utc(2025, 1, 1, 23, 0)},
+				{sourceHourly, utc(2025, 1, 1, 23, 0), utc(2025, 1, 2, 0, 0)},
+				{sourceDaily, utc(2025, 1, 2, 0, 0), utc(2025, 1, 4, 0, 0)},
+				{sourceHourly, utc(2025, 1, 4, 0, 0), utc(2025, 1, 4, 2, 0)},
+			},
+		},
+		{
+			name:     "hourly when daily not allowed",
+			start:    utc(2025, 1, 1, 0, 0),
+			end:      utc(2025, 1, 3, 0, 0),
+			coarsest: sourceHourly,
+			want:     []eventSegment{{sourceHourly, utc(2025, 1, 1, 0, 0), utc(2025, 1, 3, 0, 0)}},
+		},
+		{
+			name:     "empty range keeps one raw segment",
+			start:    utc(2025, 1, 1, 0, 0),
+			end:      utc(2025, 1, 1, 0, 0),
+			coarsest: sourceDaily,
+			want:     []eventSegment{{sourceRaw, utc(2025, 1, 1, 0, 0), utc(2025, 1, 1, 0, 0)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planSegments(tt.start, tt.end, tt.coarsest)
+			if len(got) != len(tt.want) {
+				t.Fatalf("planSegments() = %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].source != want.source || !got[i].start.Equal(want.start) || !got[i].end.Equal(want.end) {
+					t.Errorf("segment %d = %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeSeriesSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		granularity model.TimeGranularity
+		groupBy     string
+		timezone    string
+		start, end  time.Time
+		want        eventSource
+	}{
+		{name: "minute reads raw", granularity: model.GranularityMinute, timezone: "UTC", want: sourceRaw},
+		{name: "os_name reads raw", granularity: model.GranularityDay, groupBy: model.GroupByOSName, timezone: "UTC", want: sourceRaw},
+		{name: "unknown zone reads raw", granularity: model.GranularityDay, timezone: "Mars/Olympus", want: sourceRaw},
+		{name: "hour in UTC", granularity: model.GranularityHour, timezone: "UTC", want: sourceHourly},
+		{name: "day in UTC", granularity: model.GranularityDay, timezone: "UTC", want: sourceDaily},
+		{name: "week in UTC", granularity: model.GranularityWeek, timezone: "UTC", want: sourceDaily},
+		{name: "day in whole-hour zone", granularity: model.GranularityDay, timezone: "Asia/Shanghai", want: sourceHourly},
+		{name: "half-hour zone reads raw", granularity: model.GranularityHour, timezone: "Asia/Kolkata", want: sourceRaw},
+		{name: "DST zone in winter", granularity: model.GranularityDay, timezone: "America/New_York", want: sourceHourly},
+		{
+			name:        "zone with half-hour DST",
+			granularity: model.GranularityHour,
+			timezone:    "Australia/Lord_Howe",
+			start:       utc(2025, 1, 1, 0, 0),
+			end:         utc(2025, 2, 1, 0, 0),
+			want:        sourceRaw,
+		},
+		{
+			name:        "zone leaving UTC in summer",
+			granularity: model.GranularityDay,
+			timezone:    "Europe/London",
+			start:       utc(2025, 1, 1, 0, 0),
+			end:         utc(2025, 1, 8, 0, 0),
+			want:        sourceHourly,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := time.LoadLocation(tt.timezone); err != nil && tt.want != sourceRaw {
+				t.Skipf("tzdata not available: %v", err)
+			}
+			start, end := tt.start, tt.end
+			if start.IsZero() {
+				start, end = utc(2025, 1, 1, 0, 0), utc(2025, 1, 8, 0, 0)
+			}
+			req := &model.InstallTimeSeriesRequest{Granularity: tt.granularity, GroupBy: tt.groupBy, Timezone: tt.timezone}
+			if got := timeSeriesSource(req, start, end); got != tt.want {
+				t.Errorf("timeSeriesSource() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
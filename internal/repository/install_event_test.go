@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+)
+
+func TestInsertDeduplicationToken(t *testing.T) {
+	event := func(appID, eventID string) *model.InstallEvent {
+		return &model.InstallEvent{AppID: appID, EventID: eventID}
+	}
+	base := insertDeduplicationToken([]*model.InstallEvent{event("app_a", "e1"), event("app_a", "e2"), event("app_b", "e1")})
+
+	tests := []struct {
+		name   string
+		events []*model.InstallEvent
+		same   bool
+	}{
+		{name: "same batch", events: []*model.InstallEvent{event("app_a", "e1"), event("app_a", "e2"), event("app_b", "e1")}, same: true},
+		{name: "consumed in another order", events: []*model.InstallEvent{event("app_b", "e1"), event("app_a", "e2"), event("app_a", "e1")}, same: true},
+		{name: "missing event", events: []*model.InstallEvent{event("app_a", "e1"), event("app_a", "e2")}},
+		{name: "extra event", events: []*model.InstallEvent{event("app_a", "e1"), event("app_a", "e2"), event("app_b", "e1"), event("app_b", "e2")}},
+		{name: "same event id in other app", events: []*model.InstallEvent{event("app_a", "e1"), event("app_a", "e2"), event("app_c", "e1")}},
+		{name: "duplicate event in batch", events: []*model.InstallEvent{event("app_a", "e1"), event("app_a", "e2"), event("app_b", "e1"), event("app_b", "e1")}},
+		{name: "separator not ambiguous", events: []*model.InstallEvent{event("app_a:e1", ""), event("app_a", "e2"), event("app_b", "e1")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := insertDeduplicationToken(tt.events)
+			if len(got) != 64 {
+				t.Fatalf("insertDeduplicationToken() = %q, want 64 hex characters", got)
+			}
+			if (got == base) != tt.same {
+				t.Errorf("insertDeduplicationToken() same as base = %v, want %v", got == base, tt.same)
+			}
+		})
+	}
+}
@@ -19,8 +19,33 @@ type UserRepository interface {
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 }
 
+// RoleRepository 角色权限数据访问接口
+type RoleRepository interface {
+	GetByName(ctx context.Context, name string) (*model.Role, error)
+	List(ctx context.Context) ([]*model.Role, error)
+	GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error)
+	AssignToUser(ctx context.Context, userID uint64, role *model.Role) error
+	RemoveFromUser(ctx context.Context, userID uint64, role *model.Role) error
+	GetPermissionsByRoles(ctx context.Context, roleNames []string) ([]string, error)
+}
+
+// AppRepository 事件上报应用及密钥数据访问接口
+type AppRepository interface {
+	Create(ctx context.Context, app *model.App) error
+	GetByAppID(ctx context.Context, appID string) (*model.App, error)
+	List(ctx context.Context) ([]*model.App, error)
+	ExistsByAppID(ctx context.Context, appID string) (bool, error)
+	UpdateStatus(ctx context.Context, appID string, status int) error
+	CreateKey(ctx context.Context, key *model.AppKey) error
+	GetKey(ctx context.Context, appID, keyID string) (*model.AppKey, error)
+	ExpireKey(ctx context.Context, keyID string, expiresAt int64) error
+	RevokeKey(ctx context.Context, keyID string) error
+}
+
 // Repository 通用数据访问接口
 type Repository interface {
 	UserRepository() UserRepository
+	RoleRepository() RoleRepository
+	AppRepository() AppRepository
 	InstallEventRepository() InstallEventRepository
 }
\ No newline at end of file
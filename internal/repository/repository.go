@@ -10,6 +10,8 @@ type RepositoryManager struct {
 	db                    *gorm.DB
 	ch                    clickhouse.Conn
 	userRepo              UserRepository
+	roleRepo              RoleRepository
+	appRepo               AppRepository
 	installEventRepo      InstallEventRepository
 }
 
@@ -18,17 +20,24 @@ func NewRepository(db *gorm.DB) *RepositoryManager {
 	return &RepositoryManager{
 		db:       db,
 		userRepo: NewUserRepository(db),
+		roleRepo: NewRoleRepository(db),
+		appRepo:  NewAppRepository(db),
 	}
 }
 
-// NewRepositoryWithClickHouse 创建包含 ClickHouse 的 Repository 实例
+// NewRepositoryWithClickHouse 创建包含 ClickHouse 的 Repository 实例，ch 为空时不创建事件仓库
 func NewRepositoryWithClickHouse(db *gorm.DB, ch clickhouse.Conn) *RepositoryManager {
-	return &RepositoryManager{
-		db:               db,
-		ch:               ch,
-		userRepo:         NewUserRepository(db),
-		installEventRepo: NewInstallEventRepository(ch),
+	repo := &RepositoryManager{
+		db:       db,
+		ch:       ch,
+		userRepo: NewUserRepository(db),
+		roleRepo: NewRoleRepository(db),
+		appRepo:  NewAppRepository(db),
+	}
+	if ch != nil {
+		repo.installEventRepo = NewInstallEventRepository(ch)
 	}
+	return repo
 }
 
 // UserRepository 获取用户仓库
@@ -36,6 +45,16 @@ func (r *RepositoryManager) UserRepository() UserRepository {
 	return r.userRepo
 }
 
+// RoleRepository 获取角色仓库
+func (r *RepositoryManager) RoleRepository() RoleRepository {
+	return r.roleRepo
+}
+
+// AppRepository 获取应用仓库
+func (r *RepositoryManager) AppRepository() AppRepository {
+	return r.appRepo
+}
+
 // InstallEventRepository 获取安装事件仓库
 func (r *RepositoryManager) InstallEventRepository() InstallEventRepository {
 	return r.installEventRepo
@@ -52,6 +71,8 @@ func (r *RepositoryManager) Transaction(fn func(*RepositoryManager) error) error
 		txRepo := &RepositoryManager{
 			db:       tx,
 			userRepo: NewUserRepository(tx),
+			roleRepo: NewRoleRepository(tx),
+			appRepo:  NewAppRepository(tx),
 		}
 		return fn(txRepo)
 	})
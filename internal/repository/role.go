@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.New(errorsx.CodeNotFound, "Role not found")
+		}
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to get role by name", err)
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]*model.Role, error) {
+	var roles []*model.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to list roles", err)
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error) {
+	var roles []*model.Role
+	user := &model.User{BaseModel: model.BaseModel{ID: userID}}
+	if err := r.db.WithContext(ctx).Model(user).Association("Roles").Find(&roles); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to get user roles", err)
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) AssignToUser(ctx context.Context, userID uint64, role *model.Role) error {
+	user := &model.User{BaseModel: model.BaseModel{ID: userID}}
+	if err := r.db.WithContext(ctx).Model(user).Omit("Roles.*").Association("Roles").Append(role); err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to assign role", err)
+	}
+	return nil
+}
+
+func (r *roleRepository) RemoveFromUser(ctx context.Context, userID uint64, role *model.Role) error {
+	user := &model.User{BaseModel: model.BaseModel{ID: userID}}
+	if err := r.db.WithContext(ctx).Model(user).Association("Roles").Delete(role); err != nil {
+		return errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to remove role", err)
+	}
+	return nil
+}
+
+func (r *roleRepository) GetPermissionsByRoles(ctx context.Context, roleNames []string) ([]string, error) {
+	var permissions []string
+	if len(roleNames) == 0 {
+		return permissions, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("permissions").
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name IN ?", roleNames).
+		Pluck("permissions.name", &permissions).Error
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to get role permissions", err)
+	}
+	return permissions, nil
+}
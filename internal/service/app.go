@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/cryptox"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/iswangwenbin/gin-starter/pkg/signx"
+	"go.uber.org/zap"
+)
+
+const (
+	appCacheKeyPrefix       = "app_registry:"
+	appSignatureKeyPrefix   = "app_signature:"
+	appCacheTTL             = time.Minute
+	appSecretCacheTTL       = 10 * time.Minute
+	defaultReplayWindow     = 5 * time.Minute
+	defaultKeyRotationGrace = 24 * time.Hour
+)
+
+// cachedApp 缓存在 Redis 中的应用及密钥状态，不包含密钥明文或密文
+type cachedApp struct {
+	AppID  string         `json:"app_id"`
+	Status int            `json:"status"`
+	Keys   []cachedAppKey `json:"keys"`
+}
+
+type cachedAppKey struct {
+	KeyID     string `json:"key_id"`
+	Status    int    `json:"status"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// appSecrets 进程内缓存的已解密密钥，按 key_id 索引；密钥创建后不会修改，
+// 吊销和过期通过 Redis 中的密钥状态判断，不依赖此缓存失效
+var appSecrets sync.Map
+
+type cachedSecret struct {
+	secret    string
+	expiresAt time.Time
+}
+
+// AppService 事件上报应用注册与请求签名认证服务
+type AppService struct {
+	*BaseService
+	appRepo          repository.AppRepository
+	cipher           *cryptox.Cipher
+	allowPlaintext   bool
+	replayWindow     time.Duration
+	keyRotationGrace time.Duration
+}
+
+func NewAppService(base *BaseService) *AppService {
+	replayWindow := defaultReplayWindow
+	keyRotationGrace := defaultKeyRotationGrace
+	var secretCipher *cryptox.Cipher
+	allowPlaintext := false
+	if cfg := configx.GetConfig(); cfg != nil {
+		if cfg.AppAuth.ReplayWindow > 0 {
+			replayWindow = cfg.AppAuth.ReplayWindow
+		}
+		if cfg.AppAuth.KeyRotationGrace > 0 {
+			keyRotationGrace = cfg.AppAuth.KeyRotationGrace
+		}
+		// configx.Load 校验失败时已拒绝启动，此处兜底，不回退为明文保存
+		c, err := cryptox.NewCipher(cfg.AppAuth.SecretKey)
+		if err != nil {
+			base.Logger.Fatal("Invalid app_auth secret_key", zap.Error(err))
+		}
+		secretCipher = c
+		allowPlaintext = cfg.AppAuth.AllowPlaintextSecrets
+	}
+	return &AppService{
+		BaseService:      base,
+		appRepo:          base.Repo.AppRepository(),
+		cipher:           secretCipher,
+		allowPlaintext:   allowPlaintext,
+		replayWindow:     replayWindow,
+		keyRotationGrace: keyRotationGrace,
+	}
+}
+
+func (as *AppService) CreateApp(req *model.CreateAppRequest) (*model.App, error) {
+	ctx := as.Ctx
+
+	exists, err := as.appRepo.ExistsByAppID(ctx, req.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errorsx.New(errorsx.CodeConflict, "App already exists")
+	}
+
+	app := &model.App{
+		AppID:       req.AppID,
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      model.AppStatusActive,
+	}
+	if err := as.appRepo.Create(ctx, app); err != nil {
+		return nil, err
+	}
+
+	as.Logger.Info("App created", zap.String("app_id", app.AppID))
+	return app, nil
+}
+
+func (as *AppService) ListApps() ([]*model.App, error) {
+	return as.appRepo.List(as.Ctx)
+}
+
+func (as *AppService) GetApp(appID string) (*model.App, error) {
+	return as.appRepo.GetByAppID(as.Ctx, appID)
+}
+
+// UpdateStatus 启用或禁用应用，禁用后立即拒绝该应用的上报
+func (as *AppService) UpdateStatus(appID string, status int) error {
+	if err := as.appRepo.UpdateStatus(as.Ctx, appID, status); err != nil {
+		return err
+	}
+	as.invalidateApp(appID)
+
+	as.Logger.Info("App status updated", zap.String("app_id", appID), zap.Int("status", status))
+	return nil
+}
+
+// CreateKey 为应用新增一对 API Key/Secret
+func (as *AppService) CreateKey(appID string) (*model.AppKeyResponse, error) {
+	if _, err := as.appRepo.GetByAppID(as.Ctx, appID); err != nil {
+		return nil, err
+	}
+
+	key, err := as.newKey(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	as.Logger.Info("App key created", zap.String("app_id", appID), zap.String("key_id", key.KeyID))
+	return key, nil
+}
+
+// RotateKey 签发新密钥，旧密钥在宽限期内继续有效，便于客户端平滑切换
+func (as *AppService) RotateKey(appID, keyID string) (*model.AppKeyResponse, error) {
+	ctx := as.Ctx
+
+	oldKey, err := as.appRepo.GetKey(ctx, appID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !oldKey.IsUsable(time.Now().UnixMilli()) {
+		return nil, errorsx.New(errorsx.CodeBadRequest, "App key is already revoked or expired")
+	}
+
+	newKey, err := as.newKey(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(as.keyRotationGrace).UnixMilli()
+	if oldKey.ExpiresAt == 0 || oldKey.ExpiresAt > expiresAt {
+		if err := as.appRepo.ExpireKey(ctx, keyID, expiresAt); err != nil {
+			return nil, err
+		}
+	}
+	as.invalidateApp(appID)
+
+	as.Logger.Info("App key rotated",
+		zap.String("app_id", appID),
+		zap.String("old_key_id", keyID),
+		zap.String("new_key_id", newKey.KeyID),
+		zap.Duration("grace", as.keyRotationGrace))
+	return newKey, nil
+}
+
+// RevokeKey 立即吊销密钥
+func (as *AppService) RevokeKey(appID, keyID string) error {
+	ctx := as.Ctx
+
+	if _, err := as.appRepo.GetKey(ctx, appID, keyID); err != nil {
+		return err
+	}
+	if err := as.appRepo.RevokeKey(ctx, keyID); err != nil {
+		return err
+	}
+	as.invalidateApp(appID)
+
+	as.Logger.Info("App key revoked", zap.String("app_id", appID), zap.String("key_id", keyID))
+	return nil
+}
+
+// Authenticate 校验 HTTP 上报请求的签名，签名覆盖原始请求体，成功后返回已启用的应用
+func (as *AppService) Authenticate(ctx context.Context, sig *model.AppSignature, payload []byte) (*model.App, error) {
+	return as.authenticate(ctx, sig, signx.StringToSign(sig.AppID, sig.KeyID, sig.Timestamp, payload))
+}
+
+// AuthenticateGRPC 校验 gRPC 上报请求的签名，payloadSHA256 为客户端声明的载荷摘要，由调用方与收到的字节比对
+func (as *AppService) AuthenticateGRPC(ctx context.Context, sig *model.AppSignature, method, nonce, payloadSHA256 string) (*model.App, error) {
+	if nonce == "" || payloadSHA256 == "" {
+		return nil, errorsx.New(errorsx.CodeSignatureInvalid, "Missing signature headers")
+	}
+	return as.authenticate(ctx, sig, signx.GRPCStringToSign(method, sig.AppID, sig.KeyID, sig.Timestamp, nonce, payloadSHA256))
+}
+
+func (as *AppService) authenticate(ctx context.Context, sig *model.AppSignature, stringToSign string) (*model.App, error) {
+	if sig.AppID == "" || sig.KeyID == "" || sig.Signature == "" || sig.Timestamp == 0 {
+		return nil, errorsx.New(errorsx.CodeSignatureInvalid, "Missing signature headers")
+	}
+
+	skew := time.Since(time.Unix(sig.Timestamp, 0))
+	if skew > as.replayWindow || skew < -as.replayWindow {
+		return nil, errorsx.ErrSignatureExpired
+	}
+
+	app, err := as.activeApp(ctx, sig.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	nowMilli := time.Now().UnixMilli()
+	usable := false
+	for _, key := range app.Keys {
+		k := model.AppKey{Status: key.Status, ExpiresAt: key.ExpiresAt}
+		if key.KeyID == sig.KeyID && k.IsUsable(nowMilli) {
+			usable = true
+			break
+		}
+	}
+	if !usable {
+		return nil, errorsx.ErrAPIKeyInvalid
+	}
+
+	secret, err := as.keySecret(ctx, sig.AppID, sig.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !signx.VerifyString(secret, stringToSign, sig.Signature) {
+		return nil, errorsx.ErrSignatureInvalid
+	}
+
+	// 时间窗口内同一签名只允许使用一次
+	if as.Cache != nil {
+		ok, err := as.Cache.SetNX(ctx, appSignatureKeyPrefix+sig.Signature, 1, 2*as.replayWindow).Result()
+		if err != nil {
+			return nil, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to check request replay", err)
+		}
+		if !ok {
+			return nil, errorsx.ErrSignatureReplayed
+		}
+	}
+
+	return &model.App{AppID: app.AppID, Status: app.Status}, nil
+}
+
+// ValidateApp 校验应用已注册且处于启用状态
+func (as *AppService) ValidateApp(ctx context.Context, appID string) error {
+	_, err := as.activeApp(ctx, appID)
+	return err
+}
+
+func (as *AppService) activeApp(ctx context.Context, appID string) (*cachedApp, error) {
+	if appID == "" {
+		return nil, errorsx.ErrAppNotFound
+	}
+
+	app, err := as.loadApp(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Status != model.AppStatusActive {
+		return nil, errorsx.ErrAppDisabled
+	}
+	return app, nil
+}
+
+// loadApp 读取应用及密钥，Redis 短暂缓存以减轻上报高峰对 MySQL 的压力
+func (as *AppService) loadApp(ctx context.Context, appID string) (*cachedApp, error) {
+	key := appCacheKeyPrefix + appID
+
+	if as.Cache != nil {
+		if data, err := as.Cache.Get(ctx, key).Bytes(); err == nil {
+			var cached cachedApp
+			if err := json.Unmarshal(data, &cached); err == nil {
+				return &cached, nil
+			}
+		}
+	}
+
+	app, err := as.appRepo.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &cachedApp{AppID: app.AppID, Status: app.Status}
+	for _, k := range app.Keys {
+		cached.Keys = append(cached.Keys, cachedAppKey{
+			KeyID:     k.KeyID,
+			Status:    k.Status,
+			ExpiresAt: k.ExpiresAt,
+		})
+	}
+
+	if as.Cache != nil {
+		if data, err := json.Marshal(cached); err == nil {
+			if err := as.Cache.Set(ctx, key, data, appCacheTTL).Err(); err != nil {
+				as.Logger.Warn("failed to cache app", zap.String("app_id", appID), zap.Error(err))
+			}
+		}
+	}
+
+	return cached, nil
+}
+
+// keySecret 读取并解密密钥，解密结果只缓存在进程内存中，不写入 Redis
+func (as *AppService) keySecret(ctx context.Context, appID, keyID string) (string, error) {
+	if v, ok := appSecrets.Load(keyID); ok {
+		if cached := v.(cachedSecret); time.Now().Before(cached.expiresAt) {
+			return cached.secret, nil
+		}
+		appSecrets.Delete(keyID)
+	}
+
+	key, err := as.appRepo.GetKey(ctx, appID, keyID)
+	if err != nil {
+		return "", err
+	}
+	secret, err := as.cipher.Decrypt(key.Secret, key.KeyID)
+	if err != nil {
+		as.Logger.Error("Failed to decrypt app key secret", zap.String("app_id", appID), zap.String("key_id", keyID), zap.Error(err))
+		return "", errorsx.NewWithError(errorsx.CodeConfigError, "Failed to decrypt app key secret", err)
+	}
+
+	appSecrets.Store(keyID, cachedSecret{secret: secret, expiresAt: time.Now().Add(appSecretCacheTTL)})
+	return secret, nil
+}
+
+func (as *AppService) invalidateApp(appID string) {
+	if as.Cache == nil {
+		return
+	}
+	if err := as.Cache.Del(as.Ctx, appCacheKeyPrefix+appID).Err(); err != nil {
+		as.Logger.Warn("failed to invalidate app cache", zap.String("app_id", appID), zap.Error(err))
+	}
+}
+
+func (as *AppService) newKey(appID string) (*model.AppKeyResponse, error) {
+	keyID, err := randomHex(12)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to generate key id", err)
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to generate key secret", err)
+	}
+
+	// 未配置加密密钥时，只有显式开启 allow_plaintext_secrets 才允许明文保存
+	if as.cipher == nil && !as.allowPlaintext {
+		return nil, errorsx.New(errorsx.CodeInternalServerError, "App key secret encryption is not configured")
+	}
+
+	keyID = "ak_" + keyID
+	stored, err := as.cipher.Encrypt(secret, keyID)
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to encrypt key secret", err)
+	}
+
+	key := &model.AppKey{
+		AppID:  appID,
+		KeyID:  keyID,
+		Secret: stored,
+		Status: model.AppKeyStatusActive,
+	}
+	if err := as.appRepo.CreateKey(as.Ctx, key); err != nil {
+		return nil, err
+	}
+	as.invalidateApp(appID)
+
+	return &model.AppKeyResponse{
+		AppID:  appID,
+		KeyID:  key.KeyID,
+		Secret: secret,
+	}, nil
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
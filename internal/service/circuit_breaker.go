@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常读取和写入
+	BreakerOpen     = "open"      // 暂停读取，等待健康探测成功
+	BreakerHalfOpen = "half_open" // 探测成功，恢复读取，下一次写入成功后关闭
+)
+
+// circuitBreaker ClickHouse 写入熔断器，一个批次重试耗尽后打开
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    string
+	openedAt time.Time
+	opens    int64
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{state: BreakerClosed}
+}
+
+// State 返回当前状态及最近一次打开的时间
+func (b *circuitBreaker) State() (string, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.openedAt
+}
+
+// Opens 返回熔断器累计打开次数
+func (b *circuitBreaker) Opens() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.opens
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == BreakerOpen
+}
+
+func (b *circuitBreaker) open() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != BreakerOpen {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+		b.opens++
+	}
+}
+
+func (b *circuitBreaker) halfOpen() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen {
+		b.state = BreakerHalfOpen
+	}
+}
+
+func (b *circuitBreaker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = BreakerClosed
+}
+
+// backoff 返回第 attempt 次重试前的等待时长，指数增长到 max 后封顶，并在 [d/2, d] 内随机抖动
+func backoff(attempt int, initial, max time.Duration) time.Duration {
+	d := initial
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// sleepContext 等待 d，上下文取消时提前返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
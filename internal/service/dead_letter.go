@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxDeliveryAttempts 写入 ClickHouse 失败的消息最多投递次数，超过后进入死信流
+	MaxDeliveryAttempts = 5
+
+	deadLetterMaxLen      = 100000
+	deadLetterPageSize    = 100
+	defaultDeadLetterSize = 20
+)
+
+// DeadLetterService 查看、重放和清除 worker.dead_letter_key 中的死信
+type DeadLetterService struct {
+	redis    *redis.Client
+	logger   *zap.Logger
+	pipeline InstallEventPipeline
+}
+
+func NewDeadLetterService(redis *redis.Client, logger *zap.Logger) *DeadLetterService {
+	return &DeadLetterService{
+		redis:    redis,
+		logger:   logger,
+		pipeline: LoadInstallEventPipeline(),
+	}
+}
+
+// List 按 ID 顺序分页查看死信
+func (s *DeadLetterService) List(ctx context.Context, req *model.DeadLetterListRequest) (*model.DeadLetterListResponse, error) {
+	size := req.Size
+	if size <= 0 {
+		size = defaultDeadLetterSize
+	}
+	if size > deadLetterPageSize {
+		size = deadLetterPageSize
+	}
+
+	start := "-"
+	if req.After != "" {
+		start = "(" + req.After
+	}
+
+	messages, err := s.redis.XRangeN(ctx, s.pipeline.DeadLetterKey, start, "+", size).Result()
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to read dead letters", err)
+	}
+
+	total, err := s.redis.XLen(ctx, s.pipeline.DeadLetterKey).Result()
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to count dead letters", err)
+	}
+
+	resp := &model.DeadLetterListResponse{
+		Entries: make([]*model.DeadLetterEntry, 0, len(messages)),
+		Total:   total,
+	}
+	for _, message := range messages {
+		resp.Entries = append(resp.Entries, parseDeadLetter(message))
+	}
+	if int64(len(messages)) == size {
+		resp.NextID = messages[len(messages)-1].ID
+	}
+
+	return resp, nil
+}
+
+// Replay 将指定死信的原始消息重新写入安装事件流，并从死信流删除
+func (s *DeadLetterService) Replay(ctx context.Context, ids []string) (int64, error) {
+	var replayed int64
+	for _, id := range ids {
+		messages, err := s.redis.XRange(ctx, s.pipeline.DeadLetterKey, id, id).Result()
+		if err != nil {
+			return replayed, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to read dead letter", err)
+		}
+		if len(messages) == 0 {
+			continue
+		}
+		if err := s.replay(ctx, messages[0]); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// ReplayAll 重放开始时已有的全部死信，返回重放和跳过的记录数；
+// 重放后再次失败的消息会以新 ID 进入死信流，只处理到开始时的最后一条，没有可重放载荷的记录跳过并保留
+func (s *DeadLetterService) ReplayAll(ctx context.Context) (replayed, skipped int64, err error) {
+	last, err := s.redis.XRevRangeN(ctx, s.pipeline.DeadLetterKey, "+", "-", 1).Result()
+	if err != nil {
+		return 0, 0, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to read dead letters", err)
+	}
+	if len(last) == 0 {
+		return 0, 0, nil
+	}
+	end := last[0].ID
+
+	start := "-"
+	for {
+		messages, err := s.redis.XRangeN(ctx, s.pipeline.DeadLetterKey, start, end, deadLetterPageSize).Result()
+		if err != nil {
+			return replayed, skipped, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to read dead letters", err)
+		}
+		for _, message := range messages {
+			if len(parseDeadLetter(message).Payload) == 0 {
+				s.logger.Warn("Dead letter has no payload to replay, skipped", zap.String("id", message.ID))
+				skipped++
+				continue
+			}
+			if err := s.replay(ctx, message); err != nil {
+				return replayed, skipped, err
+			}
+			replayed++
+		}
+		if int64(len(messages)) < deadLetterPageSize {
+			return replayed, skipped, nil
+		}
+		start = "(" + messages[len(messages)-1].ID
+	}
+}
+
+// Purge 删除指定死信
+func (s *DeadLetterService) Purge(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	purged, err := s.redis.XDel(ctx, s.pipeline.DeadLetterKey, ids...).Result()
+	if err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to purge dead letters", err)
+	}
+	s.logger.Info("Dead letters purged", zap.Int64("count", purged))
+	return purged, nil
+}
+
+// PurgeAll 删除全部死信
+func (s *DeadLetterService) PurgeAll(ctx context.Context) (int64, error) {
+	total, err := s.redis.XLen(ctx, s.pipeline.DeadLetterKey).Result()
+	if err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to count dead letters", err)
+	}
+	if err := s.redis.Del(ctx, s.pipeline.DeadLetterKey).Err(); err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to purge dead letters", err)
+	}
+	s.logger.Info("All dead letters purged", zap.Int64("count", total))
+	return total, nil
+}
+
+// replay 原样写回原始消息字段，写入与删除在同一事务中完成
+func (s *DeadLetterService) replay(ctx context.Context, message redis.XMessage) error {
+	entry := parseDeadLetter(message)
+	if len(entry.Payload) == 0 {
+		return errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Dead letter %s has no payload to replay", message.ID))
+	}
+
+	values := make(map[string]interface{}, len(entry.Payload))
+	for k, v := range entry.Payload {
+		values[k] = v
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.pipeline.StreamKey,
+		MaxLen: s.pipeline.StreamMaxLen,
+		Approx: true,
+		Values: values,
+	})
+	pipe.XDel(ctx, s.pipeline.DeadLetterKey, message.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to replay dead letter", err)
+	}
+
+	s.logger.Info("Dead letter replayed",
+		zap.String("id", message.ID),
+		zap.String("original_id", entry.OriginalID),
+		zap.String("event_id", entry.EventID))
+	return nil
+}
+
+// deadLetter 将原始消息写入死信流并确认原消息，两步在同一事务中完成
+func deadLetter(ctx context.Context, rdb *redis.Client, pipeline InstallEventPipeline, message redis.XMessage, stage string, cause error, attempts int64) error {
+	payload := make(map[string]string, len(message.Values))
+	for k, v := range message.Values {
+		payload[k] = fmt.Sprint(v)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	pipe := rdb.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: pipeline.DeadLetterKey,
+		MaxLen: deadLetterMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"original_id": message.ID,
+			"stage":       stage,
+			"error":       cause.Error(),
+			"attempts":    attempts,
+			"failed_at":   time.Now().UnixMilli(),
+			"app_id":      payload["app_id"],
+			"event_id":    payload["event_id"],
+			"payload":     string(data),
+		},
+	})
+	pipe.XAck(ctx, pipeline.StreamKey, pipeline.ConsumerGroup, message.ID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func parseDeadLetter(message redis.XMessage) *model.DeadLetterEntry {
+	field := func(key string) string {
+		if v, ok := message.Values[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	entry := &model.DeadLetterEntry{
+		ID:         message.ID,
+		OriginalID: field("original_id"),
+		Stage:      field("stage"),
+		Error:      field("error"),
+		AppID:      field("app_id"),
+		EventID:    field("event_id"),
+	}
+	entry.Attempts, _ = strconv.ParseInt(field("attempts"), 10, 64)
+	entry.FailedAt, _ = strconv.ParseInt(field("failed_at"), 10, 64)
+	_ = json.Unmarshal([]byte(field("payload")), &entry.Payload)
+	return entry
+}
@@ -3,36 +3,52 @@ package service
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
 	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 const (
-	InstallEventStreamKey = "install_events_stream"
+	installEventDedupKeyPrefix = "install_event_dedup:"
+	defaultDedupWindow         = 24 * time.Hour
 )
 
 type InstallEventService struct {
-	redis  *redis.Client
-	logger *zap.Logger
+	redis        *redis.Client
+	logger       *zap.Logger
+	dedupEnabled bool
+	dedupWindow  time.Duration
+	streamKey    string
+	streamMaxLen int64
 }
 
 func NewInstallEventService(redis *redis.Client, logger *zap.Logger) *InstallEventService {
+	dedupEnabled := true
+	dedupWindow := defaultDedupWindow
+	if cfg := configx.GetConfig(); cfg != nil {
+		dedupEnabled = cfg.Ingestion.DedupEnabled
+		if cfg.Ingestion.DedupWindow > 0 {
+			dedupWindow = cfg.Ingestion.DedupWindow
+		}
+	}
+
+	pipeline := LoadInstallEventPipeline()
 	return &InstallEventService{
-		redis:  redis,
-		logger: logger,
+		redis:        redis,
+		logger:       logger,
+		dedupEnabled: dedupEnabled,
+		dedupWindow:  dedupWindow,
+		streamKey:    pipeline.StreamKey,
+		streamMaxLen: pipeline.StreamMaxLen,
 	}
 }
 
 // Create 创建单个安装事件 - 写入 Redis Stream
 func (s *InstallEventService) Create(ctx context.Context, req *model.CreateInstallEventRequest) error {
-
-	fmt.Printf("create install event request: %+v\n", req)
-
 	// 数据验证
 	if req.EventID == "" {
 		return errorsx.New(errorsx.CodeBadRequest, "EventID is required")
@@ -47,11 +63,26 @@ func (s *InstallEventService) Create(ctx context.Context, req *model.CreateInsta
 		return errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to serialize event data", err)
 	}
 
+	// 去重窗口内已收到相同事件时直接返回，不再入队
+	if s.dedupEnabled {
+		ok, err := s.redis.SetNX(ctx, dedupKey(req), 1, s.dedupWindow).Result()
+		if err != nil {
+			s.logger.Error("Failed to check install event duplicate",
+				zap.String("event_id", req.EventID),
+				zap.Error(err))
+			return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to check duplicate event", err)
+		}
+		if !ok {
+			return errorsx.New(errorsx.CodeDuplicateEvent, errorsx.CodeDuplicateEvent.GetMessage(),
+				map[string]interface{}{"event_id": req.EventID})
+		}
+	}
+
 	// 写入 Redis Stream
 	result := s.redis.XAdd(ctx, &redis.XAddArgs{
-		Stream: InstallEventStreamKey,
-		MaxLen: 100000, // 保留最近10万条记录
-		Approx: true,   // 使用近似裁剪，性能更好
+		Stream: s.streamKey,
+		MaxLen: s.streamMaxLen, // 按配置保留最近的记录
+		Approx: true,           // 使用近似裁剪，性能更好
 		Values: map[string]interface{}{
 			"event_id":   req.EventID,
 			"app_id":     req.AppID,
@@ -62,6 +93,7 @@ func (s *InstallEventService) Create(ctx context.Context, req *model.CreateInsta
 	})
 
 	if result.Err() != nil {
+		s.releaseDedup(ctx, req)
 		s.logger.Error("Failed to add install event to stream",
 			zap.String("event_id", req.EventID),
 			zap.String("app_id", req.AppID),
@@ -78,17 +110,15 @@ func (s *InstallEventService) Create(ctx context.Context, req *model.CreateInsta
 	return nil
 }
 
-// CreateBatch 批量创建安装事件 - 写入 Redis Stream
-func (s *InstallEventService) CreateBatch(ctx context.Context, requests []*model.CreateInstallEventRequest) error {
+// CreateBatch 批量创建安装事件 - 写入 Redis Stream，去重窗口内重复的事件不入队并在结果中返回
+func (s *InstallEventService) CreateBatch(ctx context.Context, requests []*model.CreateInstallEventRequest) (*model.InstallEventBatchResponse, error) {
+	result := &model.InstallEventBatchResponse{}
 	if len(requests) == 0 {
-		return nil
+		return result, nil
 	}
 
-	// 使用 Pipeline 批量写入
-	pipe := s.redis.Pipeline()
-	validCount := 0
-	createdAt := time.Now().Unix()
-
+	valid := make([]*model.CreateInstallEventRequest, 0, len(requests))
+	eventData := make([]string, 0, len(requests))
 	for _, req := range requests {
 		// 数据验证
 		if req.EventID == "" {
@@ -97,7 +127,7 @@ func (s *InstallEventService) CreateBatch(ctx context.Context, requests []*model
 		}
 
 		// 序列化请求数据
-		eventData, err := json.Marshal(req)
+		data, err := json.Marshal(req)
 		if err != nil {
 			s.logger.Warn("Skipping event due to marshal error",
 				zap.String("event_id", req.EventID),
@@ -105,47 +135,115 @@ func (s *InstallEventService) CreateBatch(ctx context.Context, requests []*model
 			continue
 		}
 
-		// 添加到 Pipeline
-		pipe.XAdd(ctx, &redis.XAddArgs{
-			Stream: InstallEventStreamKey,
-			MaxLen: 100000,
+		valid = append(valid, req)
+		eventData = append(eventData, string(data))
+	}
+
+	if len(valid) == 0 {
+		return nil, errorsx.New(errorsx.CodeBadRequest, "No valid events to create")
+	}
+
+	// 去重：同一批次内重复的事件同样只保留第一条
+	if s.dedupEnabled {
+		pipe := s.redis.Pipeline()
+		cmds := make([]*redis.BoolCmd, len(valid))
+		for i, req := range valid {
+			cmds[i] = pipe.SetNX(ctx, dedupKey(req), 1, s.dedupWindow)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.logger.Error("Failed to check install event duplicates",
+				zap.Int("count", len(valid)),
+				zap.Error(err))
+			return nil, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to check duplicate events", err)
+		}
+
+		queued := valid[:0]
+		queuedData := eventData[:0]
+		for i, cmd := range cmds {
+			if !cmd.Val() {
+				result.Duplicates = append(result.Duplicates, valid[i].EventID)
+				continue
+			}
+			queued = append(queued, valid[i])
+			queuedData = append(queuedData, eventData[i])
+		}
+		valid, eventData = queued, queuedData
+	}
+
+	if len(valid) == 0 {
+		s.logger.Info("Install events batch contained only duplicates",
+			zap.Int("duplicates", len(result.Duplicates)))
+		return result, nil
+	}
+
+	// 使用 Pipeline 批量写入
+	pipe := s.redis.Pipeline()
+	createdAt := time.Now().Unix()
+	xadds := make([]*redis.StringCmd, len(valid))
+	for i, req := range valid {
+		xadds[i] = pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.streamKey,
+			MaxLen: s.streamMaxLen,
 			Approx: true,
 			Values: map[string]interface{}{
 				"event_id":   req.EventID,
 				"app_id":     req.AppID,
 				"device_id":  req.DeviceID,
-				"event_data": string(eventData),
+				"event_data": eventData[i],
 				"created_at": createdAt,
 			},
 		})
-		validCount++
-	}
-
-	if validCount == 0 {
-		return errorsx.New(errorsx.CodeBadRequest, "No valid events to create")
 	}
 
 	// 执行 Pipeline
-	results, err := pipe.Exec(ctx)
-	if err != nil {
+	if _, err := pipe.Exec(ctx); err != nil {
+		// 已入队的事件保留去重标记，客户端重试时作为重复返回，只释放入队失败的事件
+		failed := failedXAdds(valid, xadds)
+		s.releaseDedup(ctx, failed...)
 		s.logger.Error("Failed to execute batch pipeline",
-			zap.Int("count", validCount),
+			zap.Int("count", len(valid)),
+			zap.Int("failed", len(failed)),
 			zap.Error(err))
-		return errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to queue events batch", err)
+		return nil, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to queue events batch", err)
 	}
 
-	// 检查结果
-	successCount := 0
-	for _, result := range results {
-		if result.Err() == nil {
-			successCount++
-		}
-	}
+	result.Accepted = len(valid)
 
 	s.logger.Info("Install events batch queued",
 		zap.Int("total", len(requests)),
-		zap.Int("valid", validCount),
-		zap.Int("success", successCount))
+		zap.Int("accepted", result.Accepted),
+		zap.Int("duplicates", len(result.Duplicates)))
 
-	return nil
+	return result, nil
+}
+
+// releaseDedup 入队失败时删除去重标记，使客户端可以重试
+func (s *InstallEventService) releaseDedup(ctx context.Context, requests ...*model.CreateInstallEventRequest) {
+	if !s.dedupEnabled || len(requests) == 0 {
+		return
+	}
+
+	keys := make([]string, len(requests))
+	for i, req := range requests {
+		keys[i] = dedupKey(req)
+	}
+	if err := s.redis.Del(ctx, keys...).Err(); err != nil {
+		s.logger.Warn("Failed to release install event dedup keys", zap.Error(err))
+	}
+}
+
+// failedXAdds 返回 XADD 命令执行失败的事件，cmds 与 requests 一一对应
+func failedXAdds(requests []*model.CreateInstallEventRequest, cmds []*redis.StringCmd) []*model.CreateInstallEventRequest {
+	var failed []*model.CreateInstallEventRequest
+	for i, cmd := range cmds {
+		if cmd.Err() != nil {
+			failed = append(failed, requests[i])
+		}
+	}
+	return failed
+}
+
+// dedupKey 去重键，event_id 在应用内唯一
+func dedupKey(req *model.CreateInstallEventRequest) string {
+	return installEventDedupKeyPrefix + req.AppID + ":" + req.EventID
 }
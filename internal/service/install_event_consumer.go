@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/iswangwenbin/gin-starter/internal/model"
@@ -13,122 +16,356 @@ import (
 )
 
 const (
-	InstallEventConsumerGroup = "install_events_consumer_group"
-	InstallEventConsumerName  = "install_events_consumer"
-	BatchSize                 = 100 // 批量处理大小
-	BatchTimeout              = 5 * time.Second
+	// maxClaimBatches 单次认领的批次数上限，剩余消息留到下一轮
+	maxClaimBatches = 10
+	// pendingRetryMinIdle 定时重试只处理空闲超过该时间的待确认消息；XCLAIM 会重置空闲时间，
+	// 同一消息两次重试之间至少间隔该时间
+	pendingRetryMinIdle = 30 * time.Second
+
+	// writeMaxAttempts 单个批次在进程内的最大写入次数，耗尽后打开熔断器
+	writeMaxAttempts  = 4
+	writeRetryInitial = 200 * time.Millisecond
+	writeRetryMax     = 5 * time.Second
+	probeRetryInitial = time.Second
+	probeRetryMax     = 30 * time.Second
+	probeTimeout      = 3 * time.Second
+	// breakerPollInterval 熔断期间读取协程检查熔断器状态的间隔
+	breakerPollInterval = time.Second
+	// drainTimeout 停止时等待写入协程处理完已读取批次的最长时间
+	drainTimeout = 30 * time.Second
 )
 
+// InstallEventConsumerStats 消费者写入重试与熔断状态
+type InstallEventConsumerStats struct {
+	BreakerState    string
+	BreakerOpenedAt time.Time
+	BreakerOpens    int64
+	WriteRetries    int64 // 批次写入失败后的重试次数
+	FailedBatches   int64 // 重试耗尽仍失败的批次数
+	ProbeFailures   int64 // 熔断期间健康探测失败次数
+	InFlight        int   // 已读取、正在等待或正在写入的消息数
+
+	EventsWritten   int64
+	BatchesWritten  int64
+	ReadErrors      int64
+	ParseErrors     int64
+	AckErrors       int64
+	DeadLettered    int64
+	LastFlush       time.Time // 最近一次成功写入 ClickHouse 的时间
+	OldestHeartbeat time.Time // 各读取循环最近一次推进时间中最早的一个
+	BatchLatency    LatencySnapshot
+}
+
+// eventBatch 待写入 ClickHouse 的一批事件及其对应的 Stream 消息
+type eventBatch struct {
+	events   []*model.InstallEvent
+	messages []redis.XMessage
+}
+
+func (b *eventBatch) add(event *model.InstallEvent, message redis.XMessage) {
+	b.events = append(b.events, event)
+	b.messages = append(b.messages, message)
+}
+
+// InstallEventConsumer 多个读取协程从 Stream 读取事件并组成批次，由写入协程池并发写入 ClickHouse
 type InstallEventConsumer struct {
 	redis            *redis.Client
 	installEventRepo repository.InstallEventRepository
 	logger           *zap.Logger
-	ctx              context.Context
-	cancel           context.CancelFunc
+	pipeline         InstallEventPipeline
+	name             string
+	readerNames      []string
+	breaker          *circuitBreaker
+	writeRetries     atomic.Int64
+	failedBatches    atomic.Int64
+	probeFailures    atomic.Int64
+	eventsWritten    atomic.Int64
+	batchesWritten   atomic.Int64
+	readErrors       atomic.Int64
+	parseErrors      atomic.Int64
+	ackErrors        atomic.Int64
+	deadLettered     atomic.Int64
+	lastFlush        atomic.Int64 // UnixNano
+	heartbeats       []atomic.Int64
+	batchLatency     *latencyHistogram
+
+	batches    chan *eventBatch
+	inFlightMu sync.Mutex
+	inFlight   map[string]struct{}
+	readers    sync.WaitGroup
+	writers    sync.WaitGroup
+	stopOnce   sync.Once
+
+	// ctx 控制读取，writeCtx 控制写入，停止时先停止读取再等待写入排空
+	ctx         context.Context
+	cancel      context.CancelFunc
+	writeCtx    context.Context
+	cancelWrite context.CancelFunc
 }
 
 func NewInstallEventConsumer(redis *redis.Client, installEventRepo repository.InstallEventRepository, logger *zap.Logger) *InstallEventConsumer {
 	ctx, cancel := context.WithCancel(context.Background())
+	writeCtx, cancelWrite := context.WithCancel(context.Background())
+
+	pipeline := LoadInstallEventPipeline()
+	name := pipeline.ConsumerName
+	if name == "" {
+		name = defaultConsumerName()
+	}
+
+	// 多个读取协程各自使用独立的消费者名称，各自维护待确认列表
+	readerNames := []string{name}
+	if pipeline.Consumers > 1 {
+		readerNames = make([]string, pipeline.Consumers)
+		for i := range readerNames {
+			readerNames[i] = fmt.Sprintf("%s-%d", name, i)
+		}
+	}
+
 	return &InstallEventConsumer{
 		redis:            redis,
 		installEventRepo: installEventRepo,
 		logger:           logger,
+		pipeline:         pipeline,
+		name:             name,
+		readerNames:      readerNames,
+		breaker:          newCircuitBreaker(),
+		heartbeats:       make([]atomic.Int64, len(readerNames)),
+		batchLatency:     newLatencyHistogram(),
+		batches:          make(chan *eventBatch, pipeline.Writers),
+		inFlight:         make(map[string]struct{}),
 		ctx:              ctx,
 		cancel:           cancel,
+		writeCtx:         writeCtx,
+		cancelWrite:      cancelWrite,
 	}
 }
 
+// defaultConsumerName 使用 主机名-PID 区分同一消费者组中的多个 Worker 进程
+func defaultConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "worker"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Name 返回消费者名称
+func (c *InstallEventConsumer) Name() string {
+	return c.name
+}
+
+// Pipeline 返回生效的 Stream 与消费参数
+func (c *InstallEventConsumer) Pipeline() InstallEventPipeline {
+	return c.pipeline
+}
+
+// Stats 返回写入重试与熔断状态
+func (c *InstallEventConsumer) Stats() InstallEventConsumerStats {
+	state, openedAt := c.breaker.State()
+	c.inFlightMu.Lock()
+	inFlight := len(c.inFlight)
+	c.inFlightMu.Unlock()
+
+	stats := InstallEventConsumerStats{
+		BreakerState:    state,
+		BreakerOpenedAt: openedAt,
+		BreakerOpens:    c.breaker.Opens(),
+		WriteRetries:    c.writeRetries.Load(),
+		FailedBatches:   c.failedBatches.Load(),
+		ProbeFailures:   c.probeFailures.Load(),
+		InFlight:        inFlight,
+		EventsWritten:   c.eventsWritten.Load(),
+		BatchesWritten:  c.batchesWritten.Load(),
+		ReadErrors:      c.readErrors.Load(),
+		ParseErrors:     c.parseErrors.Load(),
+		AckErrors:       c.ackErrors.Load(),
+		DeadLettered:    c.deadLettered.Load(),
+		BatchLatency:    c.batchLatency.snapshot(),
+	}
+	if last := c.lastFlush.Load(); last > 0 {
+		stats.LastFlush = time.Unix(0, last)
+	}
+	for i := range c.heartbeats {
+		beat := c.heartbeats[i].Load()
+		if beat == 0 {
+			continue
+		}
+		if t := time.Unix(0, beat); stats.OldestHeartbeat.IsZero() || t.Before(stats.OldestHeartbeat) {
+			stats.OldestHeartbeat = t
+		}
+	}
+	return stats
+}
+
+// Stalled 检查读取循环是否超过 stall_timeout 没有推进，未启动时同样视为停滞
+func (c *InstallEventConsumer) Stalled() (bool, time.Duration) {
+	var oldest time.Duration
+	for i := range c.heartbeats {
+		beat := c.heartbeats[i].Load()
+		if beat == 0 {
+			return true, 0
+		}
+		if age := time.Since(time.Unix(0, beat)); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest > c.pipeline.StallTimeout, oldest
+}
+
 // Start 启动消费者
 func (c *InstallEventConsumer) Start() error {
 	// 创建消费者组（如果不存在）
-	err := c.redis.XGroupCreateMkStream(c.ctx, InstallEventStreamKey, InstallEventConsumerGroup, "0").Err()
+	err := c.redis.XGroupCreateMkStream(c.ctx, c.pipeline.StreamKey, c.pipeline.ConsumerGroup, "0").Err()
 	if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
 		c.logger.Error("Failed to create consumer group", zap.Error(err))
 		return err
 	}
 
 	c.logger.Info("Install event consumer started",
-		zap.String("stream", InstallEventStreamKey),
-		zap.String("group", InstallEventConsumerGroup),
-		zap.String("consumer", InstallEventConsumerName))
+		zap.String("stream", c.pipeline.StreamKey),
+		zap.String("group", c.pipeline.ConsumerGroup),
+		zap.Strings("consumers", c.readerNames),
+		zap.Int("writers", c.pipeline.Writers),
+		zap.Int("batch_size", c.pipeline.BatchSize),
+		zap.Int64("read_count", c.pipeline.ReadCount))
+
+	for i := 0; i < c.pipeline.Writers; i++ {
+		c.writers.Add(1)
+		go c.writeLoop()
+	}
 
-	// 启动消费循环
-	go c.consumeLoop()
+	// 第一个读取协程负责认领滞留消息和清理空闲消费者
+	for i, name := range c.readerNames {
+		c.heartbeats[i].Store(time.Now().UnixNano())
+		c.readers.Add(1)
+		go c.consumeLoop(i, name, i == 0)
+	}
+
+	c.readers.Add(1)
+	go c.breakerLoop()
 
 	return nil
 }
 
-// Stop 停止消费者
+// Stop 停止读取，等待已读取的批次写入完成，超时后放弃，未确认的消息留在待确认列表中
 func (c *InstallEventConsumer) Stop() {
-	c.logger.Info("Stopping install event consumer...")
-	c.cancel()
+	c.stopOnce.Do(func() {
+		c.logger.Info("Stopping install event consumer...")
+		c.cancel()
+		c.readers.Wait()
+		close(c.batches)
+
+		done := make(chan struct{})
+		go func() {
+			c.writers.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(drainTimeout):
+			c.logger.Warn("Timed out waiting for install event writers, leaving batches pending")
+			c.cancelWrite()
+			<-done
+		}
+		c.cancelWrite()
+		c.logger.Info("Install event consumer stopped")
+	})
 }
 
-// consumeLoop 消费循环
-func (c *InstallEventConsumer) consumeLoop() {
-	batch := make([]*model.InstallEvent, 0, BatchSize)
-	messageIDs := make([]string, 0, BatchSize)
-	
-	ticker := time.NewTicker(BatchTimeout)
+// consumeLoop 读取协程，按批次大小或超时将消息交给写入协程
+func (c *InstallEventConsumer) consumeLoop(index int, name string, claims bool) {
+	defer c.readers.Done()
+
+	batch := c.newBatch()
+
+	ticker := time.NewTicker(c.pipeline.BatchTimeout)
 	defer ticker.Stop()
+	var claimC <-chan time.Time
+	if claims {
+		claimTicker := time.NewTicker(c.pipeline.ClaimInterval)
+		defer claimTicker.Stop()
+		claimC = claimTicker.C
+	}
 
+	// 启动时先处理上次运行遗留在本消费者名下的消息
+	c.retryPending(index, 0)
+
+	paused := false
 	for {
+		c.heartbeats[index].Store(time.Now().UnixNano())
+
+		// 熔断期间暂停读取；内存中的批次仍在待确认列表中，恢复后由 retryPending 重新写入
+		if c.breaker.isOpen() {
+			batch = c.newBatch()
+			paused = true
+			if !sleepContext(c.ctx, breakerPollInterval) {
+				return
+			}
+			continue
+		}
+		if paused {
+			paused = false
+			c.retryPending(index, 0)
+		}
+
 		select {
 		case <-c.ctx.Done():
-			// 处理剩余的批次
-			if len(batch) > 0 {
-				c.processBatch(batch, messageIDs)
-			}
-			c.logger.Info("Install event consumer stopped")
+			// 剩余的批次交给写入协程
+			c.submit(batch)
 			return
 
 		case <-ticker.C:
 			// 定时处理批次
-			if len(batch) > 0 {
-				c.processBatch(batch, messageIDs)
-				batch = batch[:0]
-				messageIDs = messageIDs[:0]
-			}
+			c.submit(batch)
+			batch = c.newBatch()
+			// 重试本消费者名下写入失败、尚未确认且已空闲一段时间的消息
+			c.retryPending(index, pendingRetryMinIdle)
+
+		case <-claimC:
+			// 认领已退出或卡住的消费者滞留的消息，并清理长期空闲的消费者
+			c.submit(batch)
+			batch = c.newBatch()
+			c.claimStale(name)
+			c.cleanupConsumers()
 
 		default:
 			// 读取消息
-			messages, err := c.redis.XReadGroup(c.ctx, &redis.XReadGroupArgs{
-				Group:    InstallEventConsumerGroup,
-				Consumer: InstallEventConsumerName,
-				Streams:  []string{InstallEventStreamKey, ">"},
-				Count:    10,
+			streams, err := c.redis.XReadGroup(c.ctx, &redis.XReadGroupArgs{
+				Group:    c.pipeline.ConsumerGroup,
+				Consumer: name,
+				Streams:  []string{c.pipeline.StreamKey, ">"},
+				Count:    c.pipeline.ReadCount,
 				Block:    time.Second,
 			}).Result()
 
 			if err != nil {
-				if err != redis.Nil && err != context.DeadlineExceeded {
+				if err != redis.Nil && err != context.DeadlineExceeded && c.ctx.Err() == nil {
+					c.readErrors.Add(1)
 					c.logger.Error("Failed to read from stream", zap.Error(err))
+					// 避免 Redis 不可用时空转
+					sleepContext(c.ctx, breakerPollInterval)
 				}
 				continue
 			}
 
 			// 处理消息
-			for _, stream := range messages {
+			for _, stream := range streams {
 				for _, message := range stream.Messages {
 					event, err := c.parseMessage(message)
 					if err != nil {
-						c.logger.Error("Failed to parse message",
-							zap.String("message_id", message.ID),
-							zap.Error(err))
-						// 确认消息以避免重复处理
-						c.ackMessage(message.ID)
+						// 无法解析的消息重试也不会成功，直接进入死信流
+						c.deadLetter(message, model.DeadLetterStageParse, err, 1)
 						continue
 					}
 
-					batch = append(batch, event)
-					messageIDs = append(messageIDs, message.ID)
+					batch.add(event, message)
 
 					// 批次满了，立即处理
-					if len(batch) >= BatchSize {
-						c.processBatch(batch, messageIDs)
-						batch = batch[:0]
-						messageIDs = messageIDs[:0]
-						ticker.Reset(BatchTimeout) // 重置定时器
+					if len(batch.events) >= c.pipeline.BatchSize {
+						c.submit(batch)
+						batch = c.newBatch()
+						ticker.Reset(c.pipeline.BatchTimeout) // 重置定时器
 					}
 				}
 			}
@@ -136,6 +373,221 @@ func (c *InstallEventConsumer) consumeLoop() {
 	}
 }
 
+// writeLoop 写入协程，熔断期间跳过写入，消息留在待确认列表中
+func (c *InstallEventConsumer) writeLoop() {
+	defer c.writers.Done()
+	for batch := range c.batches {
+		if !c.breaker.isOpen() {
+			c.processBatch(batch)
+		}
+		c.release(batch)
+	}
+}
+
+// breakerLoop 熔断器打开后负责探测 ClickHouse
+func (c *InstallEventConsumer) breakerLoop() {
+	defer c.readers.Done()
+	for sleepContext(c.ctx, breakerPollInterval) {
+		if c.breaker.isOpen() && !c.waitForClickHouse() {
+			return
+		}
+	}
+}
+
+func (c *InstallEventConsumer) newBatch() *eventBatch {
+	return &eventBatch{
+		events:   make([]*model.InstallEvent, 0, c.pipeline.BatchSize),
+		messages: make([]redis.XMessage, 0, c.pipeline.BatchSize),
+	}
+}
+
+// submit 标记批次中的消息为处理中并交给写入协程，写入协程繁忙时阻塞读取
+func (c *InstallEventConsumer) submit(batch *eventBatch) {
+	if len(batch.events) == 0 {
+		return
+	}
+
+	c.inFlightMu.Lock()
+	for _, message := range batch.messages {
+		c.inFlight[message.ID] = struct{}{}
+	}
+	c.inFlightMu.Unlock()
+
+	c.batches <- batch
+}
+
+// release 批次处理结束（确认或留待重试）后取消处理中标记
+func (c *InstallEventConsumer) release(batch *eventBatch) {
+	c.inFlightMu.Lock()
+	for _, message := range batch.messages {
+		delete(c.inFlight, message.ID)
+	}
+	c.inFlightMu.Unlock()
+}
+
+// retryPending 重新提交本消费者待确认列表中未在处理中、空闲至少 minIdle 的消息。
+// 通过 XCLAIM 取回消息内容，期间已被确认的消息不会被重复写入
+func (c *InstallEventConsumer) retryPending(index int, minIdle time.Duration) {
+	name := c.readerNames[index]
+	start := "-"
+	for c.ctx.Err() == nil && !c.breaker.isOpen() {
+		c.heartbeats[index].Store(time.Now().UnixNano())
+		pending, err := c.redis.XPendingExt(c.ctx, &redis.XPendingExtArgs{
+			Stream:   c.pipeline.StreamKey,
+			Group:    c.pipeline.ConsumerGroup,
+			Consumer: name,
+			Idle:     minIdle,
+			Start:    start,
+			End:      "+",
+			Count:    int64(c.pipeline.BatchSize),
+		}).Result()
+		if err != nil {
+			if err != redis.Nil && c.ctx.Err() == nil {
+				c.logger.Error("Failed to read pending messages", zap.Error(err))
+			}
+			return
+		}
+		if len(pending) == 0 {
+			return
+		}
+		start = "(" + pending[len(pending)-1].ID
+
+		ids := make([]string, 0, len(pending))
+		c.inFlightMu.Lock()
+		for _, p := range pending {
+			if _, ok := c.inFlight[p.ID]; !ok {
+				ids = append(ids, p.ID)
+			}
+		}
+		c.inFlightMu.Unlock()
+		if len(ids) == 0 {
+			continue
+		}
+
+		messages, err := c.redis.XClaim(c.ctx, &redis.XClaimArgs{
+			Stream:   c.pipeline.StreamKey,
+			Group:    c.pipeline.ConsumerGroup,
+			Consumer: name,
+			MinIdle:  minIdle,
+			Messages: ids,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil && c.ctx.Err() == nil {
+				c.logger.Error("Failed to claim pending messages", zap.Error(err))
+			}
+			return
+		}
+
+		c.retryMessages(messages)
+	}
+}
+
+// notInFlight 过滤掉本进程正在处理的消息，写入耗时超过认领阈值时避免重复提交
+func (c *InstallEventConsumer) notInFlight(messages []redis.XMessage) []redis.XMessage {
+	c.inFlightMu.Lock()
+	defer c.inFlightMu.Unlock()
+
+	filtered := messages[:0]
+	for _, message := range messages {
+		if _, ok := c.inFlight[message.ID]; !ok {
+			filtered = append(filtered, message)
+		}
+	}
+	return filtered
+}
+
+// retryMessages 解析一组待确认消息并提交写入
+func (c *InstallEventConsumer) retryMessages(pending []redis.XMessage) {
+	batch := c.newBatch()
+	for _, message := range pending {
+		// 消息已被流裁剪，没有可恢复的内容
+		if message.Values == nil {
+			c.logger.Warn("Pending message trimmed from stream", zap.String("message_id", message.ID))
+			c.ackMessage(message.ID)
+			continue
+		}
+		event, err := c.parseMessage(message)
+		if err != nil {
+			c.deadLetter(message, model.DeadLetterStageParse, err, 1)
+			continue
+		}
+		batch.add(event, message)
+	}
+
+	if len(batch.events) == 0 {
+		return
+	}
+	c.logger.Info("Retrying pending install events", zap.Int("count", len(batch.events)))
+	c.submit(batch)
+}
+
+// claimStale 将空闲超过阈值的待确认消息认领到指定消费者名下并直接提交写入，返回认领的数量。
+// XAUTOCLAIM 已返回消息内容并计一次投递，不再经 retryPending 重复 XCLAIM
+func (c *InstallEventConsumer) claimStale(name string) int {
+	claimed := 0
+	start := "0-0"
+	for claimed < maxClaimBatches*c.pipeline.BatchSize && c.ctx.Err() == nil {
+		messages, next, err := c.redis.XAutoClaim(c.ctx, &redis.XAutoClaimArgs{
+			Stream:   c.pipeline.StreamKey,
+			Group:    c.pipeline.ConsumerGroup,
+			Consumer: name,
+			MinIdle:  c.pipeline.ClaimMinIdle,
+			Start:    start,
+			Count:    int64(c.pipeline.BatchSize),
+		}).Result()
+		if err != nil {
+			if err != redis.Nil && c.ctx.Err() == nil {
+				c.logger.Error("Failed to claim idle messages", zap.Error(err))
+			}
+			break
+		}
+		claimed += len(messages)
+		c.retryMessages(c.notInFlight(messages))
+		if next == "0-0" || next == "" {
+			break
+		}
+		start = next
+	}
+
+	if claimed > 0 {
+		c.logger.Info("Claimed idle install events",
+			zap.Int("count", claimed),
+			zap.Duration("min_idle", c.pipeline.ClaimMinIdle))
+	}
+	return claimed
+}
+
+// cleanupConsumers 删除没有待确认消息且长期空闲的其他消费者
+func (c *InstallEventConsumer) cleanupConsumers() {
+	consumers, err := c.redis.XInfoConsumers(c.ctx, c.pipeline.StreamKey, c.pipeline.ConsumerGroup).Result()
+	if err != nil {
+		if c.ctx.Err() == nil {
+			c.logger.Error("Failed to list consumers", zap.Error(err))
+		}
+		return
+	}
+
+	own := make(map[string]bool, len(c.readerNames))
+	for _, name := range c.readerNames {
+		own[name] = true
+	}
+
+	for _, consumer := range consumers {
+		if own[consumer.Name] || consumer.Pending > 0 || consumer.Idle < c.pipeline.ConsumerMaxIdle {
+			continue
+		}
+		if err := c.redis.XGroupDelConsumer(c.ctx, c.pipeline.StreamKey, c.pipeline.ConsumerGroup, consumer.Name).Err(); err != nil {
+			c.logger.Error("Failed to delete idle consumer",
+				zap.String("consumer", consumer.Name),
+				zap.Error(err))
+			continue
+		}
+		c.logger.Info("Deleted idle consumer",
+			zap.String("consumer", consumer.Name),
+			zap.Duration("idle", consumer.Idle))
+	}
+}
+
 // parseMessage 解析消息
 func (c *InstallEventConsumer) parseMessage(message redis.XMessage) (*model.InstallEvent, error) {
 	eventDataStr, ok := message.Values["event_data"].(string)
@@ -155,7 +607,7 @@ func (c *InstallEventConsumer) parseMessage(message redis.XMessage) (*model.Inst
 		AppVersion:       req.AppVersion,
 		AppType:          req.AppType,
 		EventID:          req.EventID,
-		EventDate:        req.EventTime.Truncate(24 * time.Hour),
+		EventDate:        req.EventTime.UTC().Truncate(24 * time.Hour),
 		EventTime:        req.EventTime,
 		DeviceID:         req.DeviceID,
 		ChannelID:        req.ChannelID,
@@ -176,49 +628,175 @@ func (c *InstallEventConsumer) parseMessage(message redis.XMessage) (*model.Inst
 	return event, nil
 }
 
-// processBatch 批量处理事件
-func (c *InstallEventConsumer) processBatch(batch []*model.InstallEvent, messageIDs []string) {
-	if len(batch) == 0 {
-		return
+// processBatch 批量写入事件，成功后确认消息
+func (c *InstallEventConsumer) processBatch(batch *eventBatch) bool {
+	if len(batch.events) == 0 {
+		return true
 	}
 
-	c.logger.Info("Processing install events batch", zap.Int("count", len(batch)))
+	c.logger.Info("Processing install events batch", zap.Int("count", len(batch.events)))
+	started := time.Now()
+	defer func() { c.batchLatency.observe(time.Since(started)) }()
 
-	// 批量写入 ClickHouse
-	if err := c.installEventRepo.CreateBatch(c.ctx, batch); err != nil {
+	// 批量写入 ClickHouse，失败时按带抖动的指数退避重试同一批次
+	var err error
+	for attempt := 1; attempt <= writeMaxAttempts; attempt++ {
+		if err = c.installEventRepo.CreateBatch(c.writeCtx, batch.events); err == nil {
+			break
+		}
+		if attempt == writeMaxAttempts || c.writeCtx.Err() != nil {
+			break
+		}
+
+		delay := backoff(attempt, writeRetryInitial, writeRetryMax)
+		c.writeRetries.Add(1)
+		c.logger.Warn("Failed to write batch to ClickHouse, retrying",
+			zap.Int("count", len(batch.events)),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", delay),
+			zap.Error(err))
+		if !sleepContext(c.writeCtx, delay) {
+			break
+		}
+	}
+	if err != nil {
+		c.failedBatches.Add(1)
 		c.logger.Error("Failed to write batch to ClickHouse",
-			zap.Int("count", len(batch)),
+			zap.Int("count", len(batch.events)),
 			zap.Error(err))
-		return
+		c.handleBatchFailure(batch.messages, err)
+		if c.writeCtx.Err() == nil {
+			c.breaker.open()
+			c.logger.Warn("ClickHouse circuit breaker opened, pausing stream reads")
+		}
+		return false
 	}
+	c.breaker.close()
+	c.eventsWritten.Add(int64(len(batch.events)))
+	c.batchesWritten.Add(1)
+	c.lastFlush.Store(time.Now().UnixNano())
 
 	// 确认所有消息
-	for _, messageID := range messageIDs {
-		c.ackMessage(messageID)
+	ids := make([]string, len(batch.messages))
+	for i, message := range batch.messages {
+		ids[i] = message.ID
+	}
+	c.ackMessage(ids...)
+
+	c.logger.Info("Install events batch processed successfully", zap.Int("count", len(batch.events)))
+	return true
+}
+
+// waitForClickHouse 按退避间隔探测 ClickHouse，探测成功后熔断器进入半开状态；上下文取消时返回 false
+func (c *InstallEventConsumer) waitForClickHouse() bool {
+	for attempt := 1; ; attempt++ {
+		if !sleepContext(c.ctx, backoff(attempt, probeRetryInitial, probeRetryMax)) {
+			return false
+		}
+
+		ctx, cancel := context.WithTimeout(c.ctx, probeTimeout)
+		err := c.installEventRepo.Ping(ctx)
+		cancel()
+		if err == nil {
+			c.breaker.halfOpen()
+			c.logger.Info("ClickHouse health probe succeeded, resuming stream reads")
+			return true
+		}
+
+		c.probeFailures.Add(1)
+		c.logger.Warn("ClickHouse health probe failed",
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+	}
+}
+
+// handleBatchFailure 投递次数达到上限的消息进入死信流，其余消息留在待确认列表等待重试
+func (c *InstallEventConsumer) handleBatchFailure(messages []redis.XMessage, cause error) {
+	pipe := c.redis.Pipeline()
+	cmds := make([]*redis.XPendingExtCmd, len(messages))
+	for i, message := range messages {
+		cmds[i] = pipe.XPendingExt(c.writeCtx, &redis.XPendingExtArgs{
+			Stream: c.pipeline.StreamKey,
+			Group:  c.pipeline.ConsumerGroup,
+			Start:  message.ID,
+			End:    message.ID,
+			Count:  1,
+		})
+	}
+	if _, err := pipe.Exec(c.writeCtx); err != nil && err != redis.Nil {
+		c.logger.Error("Failed to read delivery counts", zap.Error(err))
+		return
 	}
 
-	c.logger.Info("Install events batch processed successfully", zap.Int("count", len(batch)))
+	for i, message := range messages {
+		pending, err := cmds[i].Result()
+		if err != nil || len(pending) == 0 {
+			continue
+		}
+		if attempts := pending[0].RetryCount; attempts >= MaxDeliveryAttempts {
+			c.deadLetter(message, model.DeadLetterStageInsert, cause, attempts)
+		}
+	}
+}
+
+// deadLetter 将消息转入死信流并确认
+func (c *InstallEventConsumer) deadLetter(message redis.XMessage, stage string, cause error, attempts int64) {
+	if stage == model.DeadLetterStageParse {
+		c.parseErrors.Add(1)
+	}
+	if err := deadLetter(c.writeCtx, c.redis, c.pipeline, message, stage, cause, attempts); err != nil {
+		c.logger.Error("Failed to dead-letter message",
+			zap.String("message_id", message.ID),
+			zap.Error(err))
+		return
+	}
+	c.deadLettered.Add(1)
+	c.logger.Warn("Install event moved to dead letter stream",
+		zap.String("message_id", message.ID),
+		zap.String("stage", stage),
+		zap.Int64("attempts", attempts),
+		zap.Error(cause))
 }
 
 // ackMessage 确认消息
-func (c *InstallEventConsumer) ackMessage(messageID string) {
-	if err := c.redis.XAck(c.ctx, InstallEventStreamKey, InstallEventConsumerGroup, messageID).Err(); err != nil {
-		c.logger.Error("Failed to ack message",
-			zap.String("message_id", messageID),
+func (c *InstallEventConsumer) ackMessage(messageIDs ...string) {
+	if err := c.redis.XAck(c.writeCtx, c.pipeline.StreamKey, c.pipeline.ConsumerGroup, messageIDs...).Err(); err != nil {
+		c.ackErrors.Add(1)
+		c.logger.Error("Failed to ack messages",
+			zap.Strings("message_ids", messageIDs),
 			zap.Error(err))
 	}
 }
 
 // GetPendingCount 获取待处理消息数量
 func (c *InstallEventConsumer) GetPendingCount() (int64, error) {
-	info, err := c.redis.XPending(c.ctx, InstallEventStreamKey, InstallEventConsumerGroup).Result()
+	info, err := c.redis.XPending(context.Background(), c.pipeline.StreamKey, c.pipeline.ConsumerGroup).Result()
 	if err != nil {
 		return 0, err
 	}
 	return info.Count, nil
 }
 
+// GetLag 获取消费者组尚未读取的消息数，Redis 无法计算时返回 -1（Redis 7.0 以下不提供，返回 0）
+func (c *InstallEventConsumer) GetLag() (int64, error) {
+	groups, err := c.redis.XInfoGroups(context.Background(), c.pipeline.StreamKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	for _, group := range groups {
+		if group.Name == c.pipeline.ConsumerGroup {
+			return group.Lag, nil
+		}
+	}
+	return 0, nil
+}
+
 // GetStreamLength 获取流长度
 func (c *InstallEventConsumer) GetStreamLength() (int64, error) {
-	return c.redis.XLen(c.ctx, InstallEventStreamKey).Result()
-}
\ No newline at end of file
+	return c.redis.XLen(context.Background(), c.pipeline.StreamKey).Result()
+}
+
+// GetDeadLetterLength 获取死信流长度
+func (c *InstallEventConsumer) GetDeadLetterLength() (int64, error) {
+	return c.redis.XLen(context.Background(), c.pipeline.DeadLetterKey).Result()
+}
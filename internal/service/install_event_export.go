@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	installEventExportKeyPrefix = "install_event_export:"
+	// exportShutdownGrace 关闭时取消任务后等待其写入失败状态的时间
+	exportShutdownGrace = 10 * time.Second
+)
+
+var (
+	// exportInstance 当前实例的主机名，导出文件写在本机的 export.dir 中
+	exportInstance, _ = os.Hostname()
+	// exportProcess 当前进程的标识，重启后不同
+	exportProcess = uuid.NewString()
+)
+
+// exportExtensions 导出格式对应的文件扩展名
+var exportExtensions = map[string]string{
+	model.ExportFormatCSV:     ".csv",
+	model.ExportFormatNDJSON:  ".ndjson",
+	model.ExportFormatParquet: ".parquet",
+}
+
+// ErrExportNotFound 导出任务不存在、已过期或属于其他用户
+var ErrExportNotFound = errorsx.New(errorsx.CodeNotFound, "Export not found")
+
+// errExportShutdown 服务关闭时中断的导出任务
+var errExportShutdown = errorsx.New(errorsx.CodeServiceUnavailable, "Export interrupted by server shutdown")
+
+// InstallEventExportService 将安装事件导出为 CSV、NDJSON 或 Parquet，结果由 ClickHouse 流式编码，不在内存中缓存
+type InstallEventExportService struct {
+	*BaseService
+	eventRepo repository.InstallEventRepository
+	config    configx.ExportConfig
+	slots     chan struct{} // 限制本进程同时执行的导出任务数
+
+	// 后台任务的上下文，Shutdown 超时后取消
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	closed bool
+	jobs   sync.WaitGroup
+}
+
+func NewInstallEventExportService(base *BaseService) *InstallEventExportService {
+	config := configx.GetConfig().Export
+	ctx, cancel := context.WithCancel(context.Background())
+	return &InstallEventExportService{
+		BaseService: base,
+		eventRepo:   base.Repo.InstallEventRepository(),
+		config:      config,
+		slots:       make(chan struct{}, max(config.MaxConcurrent, 1)),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Export 同步导出到 w，供命令行写入标准输出，返回写入的字节数
+func (es *InstallEventExportService) Export(ctx context.Context, req *model.InstallEventExportRequest, w io.Writer) (int64, error) {
+	start, end, err := es.prepare(req)
+	if err != nil {
+		return 0, err
+	}
+	return es.eventRepo.Export(ctx, &req.InstallEventListRequest, start, end, req.Format, w)
+}
+
+// ExportToDir 同步导出到目录，文件名为 install_events_<开始时间>_<结束时间>.<ext>，返回文件路径和写入的字节数
+func (es *InstallEventExportService) ExportToDir(ctx context.Context, req *model.InstallEventExportRequest, dir string) (string, int64, error) {
+	start, end, err := es.prepare(req)
+	if err != nil {
+		return "", 0, err
+	}
+
+	const layout = "20060102T150405Z"
+	name := "install_events_" + start.UTC().Format(layout) + "_" + end.UTC().Format(layout) + exportExtensions[req.Format]
+	written, err := es.writeFile(ctx, &req.InstallEventListRequest, start, end, req.Format, dir, name)
+	if err != nil {
+		return "", 0, err
+	}
+	return filepath.Join(dir, name), written, nil
+}
+
+// Create 创建异步导出任务并在后台执行，任务状态保存在 Redis 中，export.ttl 后过期
+func (es *InstallEventExportService) Create(ctx context.Context, userID uint64, req *model.InstallEventExportRequest) (*model.InstallEventExport, error) {
+	if es.Cache == nil {
+		return nil, errorsx.New(errorsx.CodeServiceUnavailable, "Redis is not available")
+	}
+	start, end, err := es.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	job := &model.InstallEventExport{
+		ID:        uuid.NewString(),
+		Status:    model.ExportStatusPending,
+		Format:    req.Format,
+		UserID:    userID,
+		StartTime: start.UTC(),
+		EndTime:   end.UTC(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(es.config.TTL),
+		Instance:  exportInstance,
+		Process:   exportProcess,
+	}
+
+	// 在锁内登记任务，Shutdown 之后不再启动新任务
+	es.mu.Lock()
+	if es.closed {
+		es.mu.Unlock()
+		return nil, errorsx.New(errorsx.CodeServiceUnavailable, "Server is shutting down")
+	}
+	es.jobs.Add(1)
+	es.mu.Unlock()
+
+	if err := es.save(ctx, job); err != nil {
+		es.jobs.Done()
+		return nil, err
+	}
+
+	filters := req.InstallEventListRequest
+	go func() {
+		defer es.jobs.Done()
+		es.run(job, &filters)
+	}()
+
+	es.Logger.Info("Install event export created",
+		zap.String("export_id", job.ID),
+		zap.Uint64("user_id", userID),
+		zap.String("format", job.Format))
+	return job, nil
+}
+
+// Get 查询导出任务，只能查询自己创建的任务
+func (es *InstallEventExportService) Get(ctx context.Context, userID uint64, id string) (*model.InstallEventExport, error) {
+	if es.Cache == nil {
+		return nil, errorsx.New(errorsx.CodeServiceUnavailable, "Redis is not available")
+	}
+
+	data, err := es.Cache.Get(ctx, installEventExportKeyPrefix+id).Bytes()
+	if stderrors.Is(err, redis.Nil) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to get export", err)
+	}
+
+	var job model.InstallEventExport
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to decode export", err)
+	}
+	if job.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+	return &job, nil
+}
+
+// File 返回已完成任务的导出文件路径
+func (es *InstallEventExportService) File(ctx context.Context, userID uint64, id string) (*model.InstallEventExport, string, error) {
+	job, err := es.Get(ctx, userID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if job.Status != model.ExportStatusCompleted {
+		return nil, "", errorsx.New(errorsx.CodeConflict, fmt.Sprintf("Export is %s", job.Status))
+	}
+
+	path := filepath.Join(es.config.Dir, job.FileName)
+	if _, err := os.Stat(path); err != nil {
+		// export.dir 不是共享存储时，文件只存在于执行任务的实例上
+		if job.Instance != exportInstance {
+			return nil, "", errorsx.New(errorsx.CodeNotFound, fmt.Sprintf("Export file is stored on instance %s", job.Instance))
+		}
+		return nil, "", errorsx.New(errorsx.CodeNotFound, "Export file not found")
+	}
+	return job, path, nil
+}
+
+// prepare 校验格式并补全时间范围，格式默认为 csv
+func (es *InstallEventExportService) prepare(req *model.InstallEventExportRequest) (time.Time, time.Time, error) {
+	if es.eventRepo == nil {
+		return time.Time{}, time.Time{}, ErrClickHouseUnavailable
+	}
+	if req.Format == "" {
+		req.Format = model.ExportFormatCSV
+	}
+	req.Format = strings.ToLower(req.Format)
+	if _, ok := exportExtensions[req.Format]; !ok {
+		return time.Time{}, time.Time{}, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Unsupported export format %q, expected csv, ndjson or parquet", req.Format))
+	}
+	return queryRange(req.StartTime, req.EndTime, es.config.MaxRange)
+}
+
+// run 等待空闲槽位后执行导出，状态依次为 pending、running、completed 或 failed
+func (es *InstallEventExportService) run(job *model.InstallEventExport, filters *model.InstallEventListRequest) {
+	ctx, cancel := context.WithTimeout(es.ctx, es.config.Timeout)
+	defer cancel()
+
+	select {
+	case es.slots <- struct{}{}:
+		defer func() { <-es.slots }()
+	case <-ctx.Done():
+		es.finish(job, ctx.Err())
+		return
+	}
+
+	es.removeExpired()
+
+	startedAt := time.Now().UTC()
+	job.Status, job.StartedAt = model.ExportStatusRunning, &startedAt
+	if err := es.save(ctx, job); err != nil {
+		es.Logger.Warn("Failed to update export status", zap.String("export_id", job.ID), zap.Error(err))
+	}
+
+	fileName := "install_events_" + job.ID + exportExtensions[job.Format]
+	written, err := es.writeFile(ctx, filters, job.StartTime, job.EndTime, job.Format, es.config.Dir, fileName)
+	if err == nil {
+		job.FileName, job.Bytes = fileName, written
+	}
+	es.finish(job, err)
+}
+
+// writeFile 先写入临时文件，完成后重命名为 dir/name，返回写入的字节数
+func (es *InstallEventExportService) writeFile(ctx context.Context, filters *model.InstallEventListRequest, start, end time.Time, format, dir, name string) (int64, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to create export directory", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+strings.TrimSuffix(name, filepath.Ext(name))+"-*")
+	if err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to create export file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := es.eventRepo.Export(ctx, filters, start, end, format, tmp)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to write export file", closeErr)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return 0, errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to save export file", err)
+	}
+	return written, nil
+}
+
+// finish 记录任务结果，使用独立的上下文，超时或取消的任务也能写入失败状态
+func (es *InstallEventExportService) finish(job *model.InstallEventExport, err error) {
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	if err != nil && es.ctx.Err() != nil {
+		err = errExportShutdown
+	}
+	if err != nil {
+		job.Status, job.Error = model.ExportStatusFailed, err.Error()
+		es.Logger.Error("Install event export failed", zap.String("export_id", job.ID), zap.Error(err))
+	} else {
+		job.Status = model.ExportStatusCompleted
+		var duration time.Duration
+		if job.StartedAt != nil {
+			duration = finishedAt.Sub(*job.StartedAt)
+		}
+		es.Logger.Info("Install event export completed",
+			zap.String("export_id", job.ID),
+			zap.String("file", job.FileName),
+			zap.Int64("bytes", job.Bytes),
+			zap.Duration("duration", duration))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := es.save(ctx, job); err != nil {
+		es.Logger.Error("Failed to save export result", zap.String("export_id", job.ID), zap.Error(err))
+	}
+}
+
+// Shutdown 停止接受新任务并等待执行中的任务完成，ctx 结束时取消剩余任务，任务记录为失败
+func (es *InstallEventExportService) Shutdown(ctx context.Context) error {
+	es.mu.Lock()
+	es.closed = true
+	es.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		es.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	es.Logger.Warn("Cancelling running install event exports")
+	es.cancel()
+	select {
+	case <-done:
+	case <-time.After(exportShutdownGrace):
+		es.Logger.Warn("Install event exports did not stop in time")
+	}
+	return ctx.Err()
+}
+
+// FailOrphaned 将无人执行的未完成任务标记为失败：本实例上次运行遗留的任务，
+// 以及创建后超过 export.timeout 仍未结束的任务（执行实例已退出），返回处理的任务数
+func (es *InstallEventExportService) FailOrphaned(ctx context.Context) (int, error) {
+	if es.Cache == nil {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	failed := 0
+	iter := es.Cache.Scan(ctx, 0, installEventExportKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		data, err := es.Cache.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			continue
+		}
+		var job model.InstallEventExport
+		if err := json.Unmarshal(data, &job); err != nil {
+			continue
+		}
+		if !exportOrphaned(&job, now, es.config.Timeout) {
+			continue
+		}
+
+		finishedAt := now
+		job.Status, job.Error, job.FinishedAt = model.ExportStatusFailed, "Export interrupted, owning instance stopped", &finishedAt
+		if err := es.save(ctx, &job); err != nil {
+			return failed, err
+		}
+		failed++
+		es.Logger.Warn("Orphaned install event export failed",
+			zap.String("export_id", job.ID),
+			zap.String("instance", job.Instance))
+	}
+	if err := iter.Err(); err != nil {
+		return failed, errorsx.NewWithError(errorsx.CodeRedisError, "Failed to scan exports", err)
+	}
+	return failed, nil
+}
+
+// exportOrphaned 判断未完成的任务是否已无人执行
+func exportOrphaned(job *model.InstallEventExport, now time.Time, timeout time.Duration) bool {
+	if job.Status != model.ExportStatusPending && job.Status != model.ExportStatusRunning {
+		return false
+	}
+	if job.Instance == exportInstance && job.Process != exportProcess {
+		return true
+	}
+	// 任务的执行上下文在 export.timeout 后结束，再留一分钟写入结果
+	return now.After(job.CreatedAt.Add(timeout + time.Minute))
+}
+
+// save 写入任务状态，过期时间与任务的 ExpiresAt 一致
+func (es *InstallEventExportService) save(ctx context.Context, job *model.InstallEventExport) error {
+	ttl := time.Until(job.ExpiresAt)
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to encode export", err)
+	}
+	if err := es.Cache.Set(ctx, installEventExportKeyPrefix+job.ID, data, ttl).Err(); err != nil {
+		return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to save export", err)
+	}
+	return nil
+}
+
+// removeExpired 删除导出目录中超过 export.ttl 的文件，包括中断任务遗留的临时文件
+func (es *InstallEventExportService) removeExpired() {
+	entries, err := os.ReadDir(es.config.Dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-es.config.TTL)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.Contains(entry.Name(), "install_events_") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(es.config.Dir, entry.Name())); err != nil {
+			es.Logger.Warn("Failed to remove expired export file", zap.String("file", entry.Name()), zap.Error(err))
+		}
+	}
+}
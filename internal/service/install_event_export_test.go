@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+)
+
+func TestExportOrphaned(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeout := 30 * time.Minute
+	recent, stale := now.Add(-time.Minute), now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		job  model.InstallEventExport
+		want bool
+	}{
+		{name: "running in this process", job: model.InstallEventExport{Status: model.ExportStatusRunning, Instance: exportInstance, Process: exportProcess, CreatedAt: recent}},
+		{name: "running in previous process on this instance", job: model.InstallEventExport{Status: model.ExportStatusRunning, Instance: exportInstance, Process: "previous", CreatedAt: recent}, want: true},
+		{name: "pending in previous process on this instance", job: model.InstallEventExport{Status: model.ExportStatusPending, Instance: exportInstance, Process: "previous", CreatedAt: recent}, want: true},
+		{name: "running on other instance within timeout", job: model.InstallEventExport{Status: model.ExportStatusRunning, Instance: "other", Process: "other", CreatedAt: recent}},
+		{name: "running on other instance past timeout", job: model.InstallEventExport{Status: model.ExportStatusRunning, Instance: "other", Process: "other", CreatedAt: stale}, want: true},
+		{name: "completed", job: model.InstallEventExport{Status: model.ExportStatusCompleted, Instance: exportInstance, Process: "previous", CreatedAt: stale}},
+		{name: "failed", job: model.InstallEventExport{Status: model.ExportStatusFailed, Instance: "other", CreatedAt: stale}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportOrphaned(&tt.job, now, timeout); got != tt.want {
+				t.Errorf("exportOrphaned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
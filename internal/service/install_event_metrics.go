@@ -0,0 +1,53 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// BatchLatencyBuckets 批次写入耗时直方图的桶上界（秒）
+var BatchLatencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// LatencySnapshot 耗时直方图快照，Counts 为各桶的累计计数，与 BatchLatencyBuckets 一一对应
+type LatencySnapshot struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64 // 秒
+}
+
+// latencyHistogram 并发安全的耗时直方图
+type latencyHistogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{counts: make([]uint64, len(BatchLatencyBuckets))}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range BatchLatencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (h *latencyHistogram) snapshot() LatencySnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return LatencySnapshot{
+		Buckets: BatchLatencyBuckets,
+		Counts:  append([]uint64(nil), h.counts...),
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+}
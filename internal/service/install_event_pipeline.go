@@ -0,0 +1,115 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+)
+
+const (
+	// InstallEventStreamKey 默认的安装事件 Stream
+	InstallEventStreamKey = "install_events_stream"
+	// InstallEventConsumerGroup 默认的消费者组
+	InstallEventConsumerGroup = "install_events_consumer_group"
+
+	defaultStreamMaxLen    = 100000
+	defaultBatchSize       = 100
+	defaultBatchTimeout    = 5 * time.Second
+	defaultReadCount       = 10
+	defaultClaimInterval   = 30 * time.Second
+	defaultClaimMinIdle    = 5 * time.Minute
+	defaultConsumerMaxIdle = 24 * time.Hour
+	defaultStallTimeout    = 2 * time.Minute
+)
+
+// InstallEventPipeline 安装事件 Stream 写入与消费参数
+type InstallEventPipeline struct {
+	StreamKey       string
+	DeadLetterKey   string
+	StreamMaxLen    int64
+	ConsumerGroup   string
+	BatchSize       int
+	BatchTimeout    time.Duration
+	ReadCount       int64
+	Consumers       int
+	Writers         int
+	ConsumerName    string
+	ClaimInterval   time.Duration
+	ClaimMinIdle    time.Duration
+	ConsumerMaxIdle time.Duration
+	StallTimeout    time.Duration
+}
+
+// LoadInstallEventPipeline 读取 worker 配置，未配置的项使用默认值
+func LoadInstallEventPipeline() InstallEventPipeline {
+	p := InstallEventPipeline{
+		StreamKey:       InstallEventStreamKey,
+		StreamMaxLen:    defaultStreamMaxLen,
+		ConsumerGroup:   InstallEventConsumerGroup,
+		BatchSize:       defaultBatchSize,
+		BatchTimeout:    defaultBatchTimeout,
+		ReadCount:       defaultReadCount,
+		Consumers:       1,
+		Writers:         1,
+		ClaimInterval:   defaultClaimInterval,
+		ClaimMinIdle:    defaultClaimMinIdle,
+		ConsumerMaxIdle: defaultConsumerMaxIdle,
+		StallTimeout:    defaultStallTimeout,
+	}
+
+	cfg := configx.GetConfig()
+	if cfg == nil {
+		p.DeadLetterKey = deadLetterKey(p.StreamKey)
+		return p
+	}
+
+	w := cfg.Worker
+	if w.StreamKey != "" {
+		p.StreamKey = w.StreamKey
+	}
+	p.DeadLetterKey = w.DeadLetterKey
+	if p.DeadLetterKey == "" {
+		p.DeadLetterKey = deadLetterKey(p.StreamKey)
+	}
+	if w.StreamMaxLen > 0 {
+		p.StreamMaxLen = w.StreamMaxLen
+	}
+	if w.ConsumerGroup != "" {
+		p.ConsumerGroup = w.ConsumerGroup
+	}
+	if w.BatchSize > 0 {
+		p.BatchSize = w.BatchSize
+	}
+	if w.BatchTimeout > 0 {
+		p.BatchTimeout = w.BatchTimeout
+	}
+	if w.ReadCount > 0 {
+		p.ReadCount = w.ReadCount
+	}
+	if w.Consumers > 0 {
+		p.Consumers = w.Consumers
+	}
+	if w.Writers > 0 {
+		p.Writers = w.Writers
+	}
+	p.ConsumerName = w.ConsumerName
+	if w.ClaimInterval > 0 {
+		p.ClaimInterval = w.ClaimInterval
+	}
+	if w.ClaimMinIdle > 0 {
+		p.ClaimMinIdle = w.ClaimMinIdle
+	}
+	if w.ConsumerMaxIdle > 0 {
+		p.ConsumerMaxIdle = w.ConsumerMaxIdle
+	}
+	if w.StallTimeout > 0 {
+		p.StallTimeout = w.StallTimeout
+	}
+	return p
+}
+
+// deadLetterKey 由安装事件 Stream 推导死信流，默认 Stream install_events_stream 对应 install_events_dead_letter
+func deadLetterKey(streamKey string) string {
+	return strings.TrimSuffix(streamKey, "_stream") + "_dead_letter"
+}
@@ -0,0 +1,22 @@
+package service
+
+import "testing"
+
+func TestDeadLetterKey(t *testing.T) {
+	tests := []struct {
+		streamKey string
+		want      string
+	}{
+		{streamKey: InstallEventStreamKey, want: "install_events_dead_letter"},
+		{streamKey: "tenant_a:install_events_stream", want: "tenant_a:install_events_dead_letter"},
+		{streamKey: "events", want: "events_dead_letter"},
+		{streamKey: "events_stream_v2", want: "events_stream_v2_dead_letter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.streamKey, func(t *testing.T) {
+			if got := deadLetterKey(tt.streamKey); got != tt.want {
+				t.Errorf("deadLetterKey(%q) = %q, want %q", tt.streamKey, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultEventQueryRange 未指定开始时间时向前查询的时长
+	defaultEventQueryRange = 7 * 24 * time.Hour
+	// maxEventQueryRange 单次查询允许的最大时间跨度
+	maxEventQueryRange = 93 * 24 * time.Hour
+
+	installStatsKeyPrefix = "install_stats:"
+	// installStatsCacheTTL 统计结果缓存时长，看板轮询在此时间内直接读缓存
+	installStatsCacheTTL = 30 * time.Second
+	// topChannelsLimit 渠道排行返回的数量
+	topChannelsLimit = 10
+
+	// maxTimeSeriesBuckets 单个序列允许的最大时间桶数
+	maxTimeSeriesBuckets = 5000
+	// maxTimeSeriesGroups 分组时按总量保留的最大序列数
+	maxTimeSeriesGroups = 50
+)
+
+// ErrClickHouseUnavailable 未初始化 ClickHouse 时查询事件返回的错误
+var ErrClickHouseUnavailable = errorsx.New(errorsx.CodeServiceUnavailable, "ClickHouse is not available")
+
+// InstallEventQueryService 从 ClickHouse 查询安装事件
+type InstallEventQueryService struct {
+	*BaseService
+	eventRepo repository.InstallEventRepository
+}
+
+func NewInstallEventQueryService(base *BaseService) *InstallEventQueryService {
+	return &InstallEventQueryService{
+		BaseService: base,
+		eventRepo:   base.Repo.InstallEventRepository(),
+	}
+}
+
+// List 按 (event_time, event_id) 倒序游标分页查询事件
+func (qs *InstallEventQueryService) List(ctx context.Context, req *model.InstallEventListRequest) (*model.InstallEventListResponse, error) {
+	if qs.eventRepo == nil {
+		return nil, ErrClickHouseUnavailable
+	}
+
+	start, end, err := eventQueryRange(req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *model.InstallEventCursor
+	if req.Cursor != "" {
+		if after, err = decodeEventCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// 多取一条用于判断是否还有下一页
+	limit := req.GetLimit()
+	events, err := qs.eventRepo.List(ctx, req, start, end, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.InstallEventListResponse{Events: events}
+	if len(events) > limit {
+		resp.Events = events[:limit]
+		resp.HasMore = true
+		last := resp.Events[limit-1]
+		resp.NextCursor = encodeEventCursor(&model.InstallEventCursor{EventTime: last.EventTime, EventID: last.EventID})
+	}
+
+	return resp, nil
+}
+
+// Stats 统计时间窗口内的安装数据，结果在 Redis 中短暂缓存
+func (qs *InstallEventQueryService) Stats(ctx context.Context, req *model.InstallStatsRequest) (*model.InstallStatsResponse, error) {
+	if qs.eventRepo == nil {
+		return nil, ErrClickHouseUnavailable
+	}
+
+	// 未指定结束时间时按缓存时长对齐，使窗口内的重复请求命中同一缓存
+	endTime := req.EndTime
+	if endTime == nil {
+		now := time.Now().Truncate(installStatsCacheTTL)
+		endTime = &now
+	}
+	start, end, err := eventQueryRange(req.StartTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	key := installStatsCacheKey(req, start, end)
+	if qs.Cache != nil {
+		if data, err := qs.Cache.Get(ctx, key).Bytes(); err == nil {
+			var cached model.InstallStatsResponse
+			if err := json.Unmarshal(data, &cached); err == nil {
+				return &cached, nil
+			}
+		}
+	}
+
+	stats, err := qs.eventRepo.Stats(ctx, req, start, end, topChannelsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if qs.Cache != nil {
+		if data, err := json.Marshal(stats); err == nil {
+			if err := qs.Cache.Set(ctx, key, data, installStatsCacheTTL).Err(); err != nil {
+				qs.Logger.Warn("failed to cache install stats", zap.Error(err))
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// TimeSeries 按粒度、分组维度和调用方时区查询安装数与成功率，返回补零后的完整序列
+func (qs *InstallEventQueryService) TimeSeries(ctx context.Context, req *model.InstallTimeSeriesRequest) (*model.InstallTimeSeriesResponse, error) {
+	if qs.eventRepo == nil {
+		return nil, ErrClickHouseUnavailable
+	}
+
+	if req.Granularity == "" {
+		req.Granularity = model.GranularityHour
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Invalid timezone %q", req.Timezone))
+	}
+
+	start, end, err := eventQueryRange(req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := timeBuckets(start, end, req.Granularity, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := qs.eventRepo.TimeSeries(ctx, req, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按分组汇总，再按总量保留前 maxTimeSeriesGroups 个分组
+	type groupData struct {
+		total  int64
+		points map[int64]*model.InstallTimeSeriesRow
+	}
+	groups := make(map[string]*groupData)
+	for _, row := range rows {
+		g, ok := groups[row.Group]
+		if !ok {
+			g = &groupData{points: make(map[int64]*model.InstallTimeSeriesRow)}
+			groups[row.Group] = g
+		}
+		g.total += row.Total
+		g.points[row.Bucket.Unix()] = row
+	}
+	// 未分组且无数据时仍返回一条全零序列
+	if req.GroupBy == "" && len(groups) == 0 {
+		groups[""] = &groupData{points: map[int64]*model.InstallTimeSeriesRow{}}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if groups[names[i]].total != groups[names[j]].total {
+			return groups[names[i]].total > groups[names[j]].total
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > maxTimeSeriesGroups {
+		names = names[:maxTimeSeriesGroups]
+	}
+
+	resp := &model.InstallTimeSeriesResponse{
+		Granularity: req.Granularity,
+		GroupBy:     req.GroupBy,
+		Timezone:    req.Timezone,
+		Series:      make([]model.InstallTimeSeries, 0, len(names)),
+	}
+	for _, name := range names {
+		series := model.InstallTimeSeries{
+			Group:  name,
+			Points: make([]model.InstallMetricPoint, 0, len(buckets)),
+		}
+		for _, bucket := range buckets {
+			point := model.InstallMetricPoint{Time: bucket}
+			if row, ok := groups[name].points[bucket.Unix()]; ok {
+				point.Total = row.Total
+				point.Success = row.Success
+				if row.Total > 0 {
+					point.SuccessRate = float64(row.Success) / float64(row.Total)
+				}
+			}
+			series.Points = append(series.Points, point)
+		}
+		resp.Series = append(resp.Series, series)
+	}
+
+	return resp, nil
+}
+
+// timeBuckets 生成 [start, end) 内各时间桶在 loc 时区下的起点，与 ClickHouse 的分桶方式一致
+func timeBuckets(start, end time.Time, granularity model.TimeGranularity, loc *time.Location) ([]time.Time, error) {
+	var truncate func(time.Time) time.Time
+	var next func(time.Time) time.Time
+
+	switch granularity {
+	case model.GranularityMinute:
+		truncate = func(t time.Time) time.Time { return t.Truncate(time.Minute) }
+		next = func(t time.Time) time.Time { return t.Add(time.Minute) }
+	case model.GranularityHour:
+		// 按绝对时间减去本地分秒，避免夏令时回拨时重复的本地小时被解析回同一时刻
+		truncate = func(t time.Time) time.Time {
+			return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+		}
+		next = func(t time.Time) time.Time { return truncate(t.Add(time.Hour)) }
+	case model.GranularityDay:
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		next = func(t time.Time) time.Time { return truncate(t).AddDate(0, 0, 1) }
+	case model.GranularityWeek:
+		truncate = func(t time.Time) time.Time {
+			daysSinceMonday := (int(t.Weekday()) + 6) % 7
+			return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+		}
+		next = func(t time.Time) time.Time { return truncate(t).AddDate(0, 0, 7) }
+	default:
+		return nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Unsupported granularity %q, expected minute, hour, day or week", granularity))
+	}
+
+	var buckets []time.Time
+	for t := truncate(start.In(loc)); t.Before(end); t = next(t) {
+		if len(buckets) >= maxTimeSeriesBuckets {
+			return nil, errorsx.New(errorsx.CodeBadRequest,
+				fmt.Sprintf("Too many buckets (max %d), narrow the time range or use a coarser granularity", maxTimeSeriesBuckets))
+		}
+		buckets = append(buckets, t)
+	}
+	return buckets, nil
+}
+
+// installStatsCacheKey 由过滤条件和时间范围生成缓存键
+func installStatsCacheKey(req *model.InstallStatsRequest, start, end time.Time) string {
+	appType := ""
+	if req.AppType != nil {
+		appType = fmt.Sprint(uint8(*req.AppType))
+	}
+	raw := fmt.Sprintf("%s|%s|%s|%d|%d", req.AppID, appType, req.ChannelID, start.UnixMilli(), end.UnixMilli())
+	sum := sha256.Sum256([]byte(raw))
+	return installStatsKeyPrefix + hex.EncodeToString(sum[:16])
+}
+
+// eventQueryRange 补全并校验查询时间范围，结束时间默认为当前时间
+func eventQueryRange(startTime, endTime *time.Time) (time.Time, time.Time, error) {
+	return queryRange(startTime, endTime, maxEventQueryRange)
+}
+
+// queryRange 同 eventQueryRange，时间跨度不超过 maxRange
+func queryRange(startTime, endTime *time.Time, maxRange time.Duration) (time.Time, time.Time, error) {
+	end := time.Now()
+	if endTime != nil {
+		end = *endTime
+	}
+	start := end.Add(-defaultEventQueryRange)
+	if startTime != nil {
+		start = *startTime
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errorsx.New(errorsx.CodeBadRequest, "start_time must be before end_time")
+	}
+	if end.Sub(start) > maxRange {
+		return time.Time{}, time.Time{}, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Time range must not exceed %d days", int(maxRange/(24*time.Hour))))
+	}
+	return start, end, nil
+}
+
+// eventCursor 游标的序列化格式，时间精确到毫秒
+type eventCursor struct {
+	EventTime int64  `json:"t"`
+	EventID   string `json:"id"`
+}
+
+func encodeEventCursor(cursor *model.InstallEventCursor) string {
+	data, _ := json.Marshal(eventCursor{EventTime: cursor.EventTime.UnixMilli(), EventID: cursor.EventID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeEventCursor(s string) (*model.InstallEventCursor, error) {
+	invalid := errorsx.New(errorsx.CodeBadRequest, "Invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor eventCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.EventID == "" {
+		return nil, invalid
+	}
+	return &model.InstallEventCursor{EventTime: time.UnixMilli(cursor.EventTime).UTC(), EventID: cursor.EventID}, nil
+}
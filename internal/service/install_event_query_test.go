@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+)
+
+func TestTimeBuckets(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		start, end  string
+		granularity model.TimeGranularity
+		loc         *time.Location
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:        "hour across fall-back repeats 01:00 local",
+			start:       "2024-11-03T04:00:00Z", // 00:00 EDT
+			end:         "2024-11-03T09:00:00Z", // 04:00 EST
+			granularity: model.GranularityHour,
+			loc:         newYork,
+			want:        []string{"2024-11-03T04:00:00Z", "2024-11-03T05:00:00Z", "2024-11-03T06:00:00Z", "2024-11-03T07:00:00Z", "2024-11-03T08:00:00Z"},
+		},
+		{
+			name:        "hour across spring-forward skips 02:00 local",
+			start:       "2024-03-10T05:00:00Z", // 00:00 EST
+			end:         "2024-03-10T09:00:00Z", // 05:00 EDT
+			granularity: model.GranularityHour,
+			loc:         newYork,
+			want:        []string{"2024-03-10T05:00:00Z", "2024-03-10T06:00:00Z", "2024-03-10T07:00:00Z", "2024-03-10T08:00:00Z"},
+		},
+		{
+			name:        "hour in half-hour offset zone starts on local hour",
+			start:       "2024-01-01T00:10:00Z", // 05:40 IST
+			end:         "2024-01-01T03:00:00Z",
+			granularity: model.GranularityHour,
+			loc:         kolkata,
+			want:        []string{"2023-12-31T23:30:00Z", "2024-01-01T00:30:00Z", "2024-01-01T01:30:00Z", "2024-01-01T02:30:00Z"},
+		},
+		{
+			name:        "day across fall-back is 25 hours",
+			start:       "2024-11-02T04:00:00Z",
+			end:         "2024-11-05T05:00:00Z",
+			granularity: model.GranularityDay,
+			loc:         newYork,
+			want:        []string{"2024-11-02T04:00:00Z", "2024-11-03T04:00:00Z", "2024-11-04T05:00:00Z"},
+		},
+		{
+			name:        "day in half-hour offset zone",
+			start:       "2024-01-01T00:00:00Z", // 05:30 IST
+			end:         "2024-01-02T00:00:00Z",
+			granularity: model.GranularityDay,
+			loc:         kolkata,
+			want:        []string{"2023-12-31T18:30:00Z", "2024-01-01T18:30:00Z"},
+		},
+		{
+			name:        "week starts on monday",
+			start:       "2024-11-06T12:00:00Z", // Wednesday
+			end:         "2024-11-12T00:00:00Z",
+			granularity: model.GranularityWeek,
+			loc:         newYork,
+			want:        []string{"2024-11-04T05:00:00Z", "2024-11-11T05:00:00Z"},
+		},
+		{
+			name:        "minute",
+			start:       "2024-11-03T05:59:30Z",
+			end:         "2024-11-03T06:01:00Z",
+			granularity: model.GranularityMinute,
+			loc:         newYork,
+			want:        []string{"2024-11-03T05:59:00Z", "2024-11-03T06:00:00Z"},
+		},
+		{
+			name:        "unsupported granularity",
+			start:       "2024-11-03T00:00:00Z",
+			end:         "2024-11-04T00:00:00Z",
+			granularity: "month",
+			loc:         time.UTC,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := timeBuckets(mustParseTime(t, tt.start), mustParseTime(t, tt.end), tt.granularity, tt.loc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("timeBuckets() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("timeBuckets() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("timeBuckets() = %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if !got[i].Equal(mustParseTime(t, want)) {
+					t.Errorf("bucket %d = %s, want %s", i, got[i].UTC().Format(time.RFC3339), want)
+				}
+				if got[i].Location() != tt.loc {
+					t.Errorf("bucket %d location = %s, want %s", i, got[i].Location(), tt.loc)
+				}
+			}
+		})
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
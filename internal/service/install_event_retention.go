@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"github.com/iswangwenbin/gin-starter/pkg/clickhousex"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"go.uber.org/zap"
+)
+
+// partitionIDPattern install_events 的分区 ID 为 toYYYYMM(event_date)
+var partitionIDPattern = regexp.MustCompile(`^\d{6}$`)
+
+// RetentionService 安装事件保留策略、分区归档和按应用、设备删除
+type RetentionService struct {
+	eventRepo repository.InstallEventRepository
+	logger    *zap.Logger
+	config    configx.RetentionConfig
+}
+
+func NewRetentionService(eventRepo repository.InstallEventRepository, logger *zap.Logger) *RetentionService {
+	return &RetentionService{
+		eventRepo: eventRepo,
+		logger:    logger,
+		config:    configx.GetConfig().Retention,
+	}
+}
+
+// Config 返回保留策略配置
+func (s *RetentionService) Config() configx.RetentionConfig {
+	return s.config
+}
+
+// TTLPolicies 以 TTL 执行的策略：单个应用的策略总是以 TTL 执行，默认策略只在 ttl 模式下加入
+func (s *RetentionService) TTLPolicies() []model.RetentionPolicy {
+	policies := make([]model.RetentionPolicy, 0, len(s.config.Apps)+1)
+	for _, app := range s.config.Apps {
+		policies = append(policies, model.RetentionPolicy{AppID: app.AppID, Days: app.Days})
+	}
+	if s.config.Mode == model.RetentionModeTTL && s.config.DefaultDays > 0 {
+		policies = append(policies, model.RetentionPolicy{Days: s.config.DefaultDays})
+	}
+	return policies
+}
+
+// ApplyTTL 用当前配置替换 install_events 的 TTL，ClickHouse 会在后台按新规则清理已有数据
+func (s *RetentionService) ApplyTTL(ctx context.Context) ([]model.RetentionPolicy, error) {
+	policies := s.TTLPolicies()
+	if err := s.eventRepo.ModifyTTL(ctx, policies); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Install event TTL applied", zap.Any("policies", policies))
+	return policies, nil
+}
+
+// ListPartitions 列出 install_events 的月分区
+func (s *RetentionService) ListPartitions(ctx context.Context) ([]*model.EventPartition, error) {
+	return s.eventRepo.ListPartitions(ctx)
+}
+
+// ExpiredPartitions partition 模式下整月早于所有策略保留期的分区；
+// 分区包含所有应用的数据，只有超过最长保留天数时才能删除，默认策略为永久保留时不删除任何分区
+func (s *RetentionService) ExpiredPartitions(ctx context.Context, now time.Time) ([]*model.EventPartition, error) {
+	if s.config.Mode != model.RetentionModePartition || s.config.DefaultDays <= 0 {
+		return nil, nil
+	}
+	days := s.config.DefaultDays
+	for _, app := range s.config.Apps {
+		if app.Days > days {
+			days = app.Days
+		}
+	}
+	cutoff := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -days)
+
+	partitions, err := s.eventRepo.ListPartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var expired []*model.EventPartition
+	for _, partition := range partitions {
+		month, err := time.Parse("200601", partition.ID)
+		if err != nil {
+			continue
+		}
+		if !month.AddDate(0, 1, 0).After(cutoff) {
+			expired = append(expired, partition)
+		}
+	}
+	return expired, nil
+}
+
+// EnforcePartitions 归档（配置了 archive_dir 时）并删除过期分区，返回删除的分区 ID；归档失败或校验不通过的分区不会删除
+func (s *RetentionService) EnforcePartitions(ctx context.Context, now time.Time) ([]string, error) {
+	expired, err := s.ExpiredPartitions(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, partition := range expired {
+		if s.config.ArchiveDir != "" {
+			if _, err := s.ArchiveAndDropPartition(ctx, partition.ID, s.config.ArchiveDir, s.config.ArchiveFormat); err != nil {
+				return dropped, err
+			}
+		} else if err := s.DropPartition(ctx, partition.ID); err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, partition.ID)
+	}
+	return dropped, nil
+}
+
+// ArchiveAndDropPartition 归档后删除分区，只有归档期间分区没有写入或变更时才删除，返回归档文件路径
+func (s *RetentionService) ArchiveAndDropPartition(ctx context.Context, id, dir, format string) (string, error) {
+	path, before, err := s.archivePartition(ctx, id, dir, format)
+	if err != nil {
+		return "", err
+	}
+
+	after, err := s.eventRepo.PartitionSnapshot(ctx, id)
+	if err != nil {
+		return path, err
+	}
+	if *after != *before {
+		s.logger.Warn("Install event partition changed while archiving, not dropped",
+			zap.String("partition", id), zap.Any("before", before), zap.Any("after", after))
+		return path, errorsx.New(errorsx.CodeConflict, fmt.Sprintf("Partition %s changed while archiving, archive it again before dropping", id))
+	}
+	return path, s.DropPartition(ctx, id)
+}
+
+// ArchivePartition 将一个分区导出为本地文件：Parquet 使用 zstd 压缩，Native 由 ClickHouse 以 gzip 压缩；
+// 先写入临时文件，导出行数与分区一致后再重命名，返回文件路径
+func (s *RetentionService) ArchivePartition(ctx context.Context, id, dir, format string) (string, error) {
+	path, _, err := s.archivePartition(ctx, id, dir, format)
+	return path, err
+}
+
+// archivePartition 归档分区，同时返回导出前的分区状态
+func (s *RetentionService) archivePartition(ctx context.Context, id, dir, format string) (string, *model.PartitionSnapshot, error) {
+	if !partitionIDPattern.MatchString(id) {
+		return "", nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Invalid partition %q, expected YYYYMM", id))
+	}
+
+	// 等待查询完成后再返回，查询中途失败时得到错误状态而不是截断的文件，并得到最终的结果行数
+	req := clickhousex.ExportRequest{
+		Query:          "SELECT * FROM install_events WHERE _partition_id = {partition:String}",
+		Params:         map[string]string{"partition": id},
+		WaitEndOfQuery: true,
+	}
+	var ext string
+	switch format {
+	case model.ArchiveFormatParquet:
+		req.Format, ext = "Parquet", ".parquet"
+		req.Settings = map[string]string{"output_format_parquet_compression_method": "zstd"}
+	case model.ArchiveFormatNative:
+		req.Format, ext, req.Gzip = "Native", ".native.gz", true
+	default:
+		return "", nil, errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Unsupported archive format %q", format))
+	}
+
+	snapshot, err := s.eventRepo.PartitionSnapshot(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to create archive directory", err)
+	}
+	path := filepath.Join(dir, "install_events_"+id+ext)
+	tmp, err := os.CreateTemp(dir, ".install_events_"+id+"-*")
+	if err != nil {
+		return "", nil, errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to create archive file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	started := time.Now()
+	result, err := clickhousex.Export(ctx, req, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", nil, errorsx.NewWithError(errorsx.CodeDatabaseError, "Failed to archive install event partition", err)
+	}
+	if !result.HasRows || result.ResultRows != snapshot.Rows {
+		return "", nil, errorsx.New(errorsx.CodeDatabaseError,
+			fmt.Sprintf("Archived %d rows of partition %s, expected %d", result.ResultRows, id, snapshot.Rows))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", nil, errorsx.NewWithError(errorsx.CodeFileSystemError, "Failed to save archive file", err)
+	}
+
+	s.logger.Info("Install event partition archived",
+		zap.String("partition", id),
+		zap.String("path", path),
+		zap.Uint64("rows", result.ResultRows),
+		zap.Int64("bytes", result.Bytes),
+		zap.Duration("duration", time.Since(started)))
+	return path, snapshot, nil
+}
+
+// DropPartition 删除一个月分区
+func (s *RetentionService) DropPartition(ctx context.Context, id string) error {
+	if !partitionIDPattern.MatchString(id) {
+		return errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Invalid partition %q, expected YYYYMM", id))
+	}
+	if err := s.eventRepo.DropPartition(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Install event partition dropped", zap.String("partition", id))
+	return nil
+}
+
+// RollupBackfillPartitions 含有汇总物化视图创建之前写入的明细、需要回填的分区
+func (s *RetentionService) RollupBackfillPartitions(ctx context.Context) ([]string, error) {
+	return s.eventRepo.RollupBackfillPartitions(ctx)
+}
+
+// RebuildRollups 以去重后的明细重建一个月分区的汇总，用于迁移后回填和修正重复写入造成的多计
+func (s *RetentionService) RebuildRollups(ctx context.Context, id string) error {
+	if !partitionIDPattern.MatchString(id) {
+		return errorsx.New(errorsx.CodeBadRequest, fmt.Sprintf("Invalid partition %q, expected YYYYMM", id))
+	}
+	started := time.Now()
+	cutoff, err := s.eventRepo.RebuildRollups(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Install event rollups rebuilt",
+		zap.String("partition", id),
+		zap.Time("cutoff", cutoff),
+		zap.Duration("duration", time.Since(started)))
+	return nil
+}
+
+// Purge 删除指定应用的设备数据或早于指定时间的数据，dryRun 时只返回匹配的事件数；
+// 汇总表只保存计数和去重状态，不含设备 ID，不做修改
+func (s *RetentionService) Purge(ctx context.Context, req *model.PurgeEventsRequest, dryRun bool) (uint64, error) {
+	if req.AppID == "" {
+		return 0, errorsx.New(errorsx.CodeBadRequest, "App ID is required")
+	}
+	if req.DeviceID == "" && req.Before == nil {
+		return 0, errorsx.New(errorsx.CodeBadRequest, "Device ID or before time is required")
+	}
+
+	count, err := s.eventRepo.CountEvents(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if dryRun || count == 0 {
+		return count, nil
+	}
+
+	if err := s.eventRepo.DeleteEvents(ctx, req); err != nil {
+		return 0, err
+	}
+	fields := []zap.Field{zap.String("app_id", req.AppID), zap.Uint64("count", count)}
+	if req.DeviceID != "" {
+		fields = append(fields, zap.String("device_id", req.DeviceID))
+	}
+	if req.Before != nil {
+		fields = append(fields, zap.Time("before", *req.Before))
+	}
+	s.logger.Info("Install events purged", fields...)
+	return count, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestFailedXAdds(t *testing.T) {
+	ctx := context.Background()
+	requests := []*model.CreateInstallEventRequest{
+		{AppID: "app", EventID: "e1"},
+		{AppID: "app", EventID: "e2"},
+		{AppID: "app", EventID: "e3"},
+	}
+	cmds := make([]*redis.StringCmd, len(requests))
+	for i := range cmds {
+		cmds[i] = redis.NewStringCmd(ctx)
+	}
+	cmds[1].SetErr(errors.New("OOM command not allowed"))
+
+	failed := failedXAdds(requests, cmds)
+	if len(failed) != 1 || failed[0].EventID != "e2" {
+		var ids []string
+		for _, req := range failed {
+			ids = append(ids, req.EventID)
+		}
+		t.Fatalf("failedXAdds() = %v, want [e2]", ids)
+	}
+
+	cmds[1].SetErr(nil)
+	if failed := failedXAdds(requests, cmds); len(failed) != 0 {
+		t.Errorf("failedXAdds() returned %d events, want none", len(failed))
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	rolePermissionsKeyPrefix = "rbac:role_permissions:"
+	rolePermissionsCacheTTL  = 5 * time.Minute
+)
+
+// RBACService 角色权限服务
+type RBACService struct {
+	*BaseService
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+func NewRBACService(base *BaseService) *RBACService {
+	return &RBACService{
+		BaseService: base,
+		roleRepo:    base.Repo.RoleRepository(),
+		userRepo:    base.Repo.UserRepository(),
+	}
+}
+
+func (rs *RBACService) ListRoles(ctx context.Context) ([]*model.Role, error) {
+	return rs.roleRepo.List(ctx)
+}
+
+func (rs *RBACService) GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error) {
+	return rs.roleRepo.GetUserRoles(ctx, userID)
+}
+
+// GetUserRoleNames 获取用户角色名称，用于写入 JWT
+func (rs *RBACService) GetUserRoleNames(ctx context.Context, userID uint64) ([]string, error) {
+	roles, err := rs.roleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names, nil
+}
+
+func (rs *RBACService) AssignRole(ctx context.Context, userID uint64, roleName string) error {
+	if _, err := rs.userRepo.GetByID(ctx, uint(userID)); err != nil {
+		return err
+	}
+
+	role, err := rs.roleRepo.GetByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	return rs.roleRepo.AssignToUser(ctx, userID, role)
+}
+
+func (rs *RBACService) RemoveRole(ctx context.Context, userID uint64, roleName string) error {
+	if _, err := rs.userRepo.GetByID(ctx, uint(userID)); err != nil {
+		return err
+	}
+
+	role, err := rs.roleRepo.GetByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	return rs.roleRepo.RemoveFromUser(ctx, userID, role)
+}
+
+// HasPermission 判断角色集合是否拥有指定权限，角色权限映射在 Redis 中短暂缓存
+func (rs *RBACService) HasPermission(ctx context.Context, roles []string, permission string) (bool, error) {
+	for _, role := range roles {
+		if role == model.RoleAdmin {
+			return true, nil
+		}
+	}
+
+	for _, role := range roles {
+		permissions, err := rs.rolePermissions(ctx, role)
+		if err != nil {
+			return false, err
+		}
+		for _, p := range permissions {
+			if p == permission {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// rolePermissions 获取单个角色的权限（优先读缓存）
+func (rs *RBACService) rolePermissions(ctx context.Context, role string) ([]string, error) {
+	key := rolePermissionsKeyPrefix + role
+
+	if rs.Cache != nil {
+		cached, err := rs.Cache.SMembers(ctx, key).Result()
+		if err == nil && len(cached) > 0 {
+			return cached, nil
+		}
+	}
+
+	permissions, err := rs.roleRepo.GetPermissionsByRoles(ctx, []string{role})
+	if err != nil {
+		return nil, err
+	}
+
+	if rs.Cache != nil && len(permissions) > 0 {
+		members := make([]interface{}, len(permissions))
+		for i, p := range permissions {
+			members[i] = p
+		}
+		pipe := rs.Cache.TxPipeline()
+		pipe.Del(ctx, key)
+		pipe.SAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, rolePermissionsCacheTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			rs.Logger.Warn("failed to cache role permissions", zap.String("role", role), zap.Error(err))
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iswangwenbin/gin-starter/internal/model"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"github.com/iswangwenbin/gin-starter/pkg/errorsx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	refreshTokenKeyPrefix  = "refresh_token:"
+	refreshFamilyKeyPrefix = "refresh_family:"
+	refreshUserKeyPrefix   = "refresh_user_families:"
+	defaultRefreshTTL      = 7 * 24 * time.Hour
+)
+
+// TokenService 刷新令牌服务: 不透明令牌存储在 Redis，每次使用都会轮换
+type TokenService struct {
+	*BaseService
+	refreshTTL time.Duration
+}
+
+func NewTokenService(base *BaseService) *TokenService {
+	refreshTTL := defaultRefreshTTL
+	if cfg := configx.GetConfig(); cfg != nil && cfg.JWT.RefreshTTL > 0 {
+		refreshTTL = cfg.JWT.RefreshTTL
+	}
+	return &TokenService{
+		BaseService: base,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// IssueRefreshToken 登录时签发刷新令牌，并创建新的令牌家族
+func (ts *TokenService) IssueRefreshToken(userID uint64, username string) (string, error) {
+	ctx := ts.Ctx
+
+	session := &model.RefreshSession{
+		UserID:   userID,
+		Username: username,
+		FamilyID: uuid.New().String(),
+	}
+
+	// 记录家族并挂到用户名下，便于按用户吊销
+	pipe := ts.Cache.TxPipeline()
+	pipe.Set(ctx, refreshFamilyKey(session.FamilyID), userID, ts.refreshTTL)
+	pipe.SAdd(ctx, refreshUserKey(userID), session.FamilyID)
+	pipe.Expire(ctx, refreshUserKey(userID), ts.refreshTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", errorsx.NewWithError(errorsx.CodeRedisError, "Failed to create refresh token family", err)
+	}
+
+	return ts.storeRefreshToken(session)
+}
+
+// RotateRefreshToken 使用刷新令牌换取新令牌；旧令牌被再次使用时吊销整个家族
+func (ts *TokenService) RotateRefreshToken(refreshToken string) (*model.RefreshSession, string, error) {
+	ctx := ts.Ctx
+
+	if refreshToken == "" {
+		return nil, "", errorsx.ErrRefreshTokenInvalid
+	}
+
+	key := refreshTokenKey(refreshToken)
+	values, err := ts.Cache.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, "", errorsx.NewWithError(errorsx.CodeRedisError, "Failed to load refresh token", err)
+	}
+	if len(values) == 0 {
+		return nil, "", errorsx.ErrRefreshTokenInvalid
+	}
+
+	userID, err := strconv.ParseUint(values["user_id"], 10, 64)
+	if err != nil {
+		return nil, "", errorsx.ErrRefreshTokenInvalid
+	}
+	session := &model.RefreshSession{
+		UserID:   userID,
+		Username: values["username"],
+		FamilyID: values["family_id"],
+	}
+
+	// 标记为已轮换；标记失败说明该令牌之前已被使用过
+	first, err := ts.Cache.HSetNX(ctx, key, "rotated_at", time.Now().Unix()).Result()
+	if err != nil {
+		return nil, "", errorsx.NewWithError(errorsx.CodeRedisError, "Failed to rotate refresh token", err)
+	}
+	if !first {
+		ts.Logger.Warn("refresh token reuse detected, revoking token family",
+			zap.Uint64("user_id", session.UserID),
+			zap.String("family_id", session.FamilyID))
+		if err := ts.RevokeFamily(session.FamilyID); err != nil {
+			return nil, "", err
+		}
+		return nil, "", errorsx.ErrRefreshTokenReused
+	}
+
+	// 家族已被吊销或已过期
+	exists, err := ts.Cache.Exists(ctx, refreshFamilyKey(session.FamilyID)).Result()
+	if err != nil {
+		return nil, "", errorsx.NewWithError(errorsx.CodeRedisError, "Failed to check refresh token family", err)
+	}
+	if exists == 0 {
+		return nil, "", errorsx.ErrRefreshTokenInvalid
+	}
+
+	newToken, err := ts.storeRefreshToken(session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pipe := ts.Cache.Pipeline()
+	pipe.Expire(ctx, refreshFamilyKey(session.FamilyID), ts.refreshTTL)
+	pipe.Expire(ctx, refreshUserKey(session.UserID), ts.refreshTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, "", errorsx.NewWithError(errorsx.CodeRedisError, "Failed to extend refresh token family", err)
+	}
+
+	return session, newToken, nil
+}
+
+// RevokeFamily 吊销令牌家族，家族内所有刷新令牌随之失效
+func (ts *TokenService) RevokeFamily(familyID string) error {
+	if err := ts.Cache.Del(ts.Ctx, refreshFamilyKey(familyID)).Err(); err != nil {
+		return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to revoke refresh token family", err)
+	}
+	return nil
+}
+
+// RevokeRefreshToken 吊销刷新令牌所在的家族（用于登出）
+func (ts *TokenService) RevokeRefreshToken(refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	familyID, err := ts.Cache.HGet(ts.Ctx, refreshTokenKey(refreshToken), "family_id").Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to load refresh token", err)
+	}
+
+	return ts.RevokeFamily(familyID)
+}
+
+// RevokeUserTokens 吊销用户的所有刷新令牌家族
+func (ts *TokenService) RevokeUserTokens(userID uint64) error {
+	ctx := ts.Ctx
+
+	familyIDs, err := ts.Cache.SMembers(ctx, refreshUserKey(userID)).Result()
+	if err != nil {
+		return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to load refresh token families", err)
+	}
+
+	keys := make([]string, 0, len(familyIDs)+1)
+	for _, familyID := range familyIDs {
+		keys = append(keys, refreshFamilyKey(familyID))
+	}
+	keys = append(keys, refreshUserKey(userID))
+
+	if err := ts.Cache.Del(ctx, keys...).Err(); err != nil {
+		return errorsx.NewWithError(errorsx.CodeRedisError, "Failed to revoke refresh token families", err)
+	}
+	return nil
+}
+
+// storeRefreshToken 生成随机令牌，Redis 中只保存其哈希
+func (ts *TokenService) storeRefreshToken(session *model.RefreshSession) (string, error) {
+	ctx := ts.Ctx
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errorsx.NewWithError(errorsx.CodeInternalServerError, "Failed to generate refresh token", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	key := refreshTokenKey(token)
+	pipe := ts.Cache.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"user_id":   session.UserID,
+		"username":  session.Username,
+		"family_id": session.FamilyID,
+		"issued_at": time.Now().Unix(),
+	})
+	pipe.Expire(ctx, key, ts.refreshTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", errorsx.NewWithError(errorsx.CodeRedisError, "Failed to store refresh token", err)
+	}
+
+	return token, nil
+}
+
+func refreshTokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return refreshTokenKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func refreshFamilyKey(familyID string) string {
+	return refreshFamilyKeyPrefix + familyID
+}
+
+func refreshUserKey(userID uint64) string {
+	return refreshUserKeyPrefix + strconv.FormatUint(userID, 10)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"time"
 
 	"github.com/iswangwenbin/gin-starter/internal/model"
@@ -22,6 +23,14 @@ func NewUserService(base *BaseService) *UserService {
 	}
 }
 
+// WithContext 返回绑定请求上下文的副本，并发请求之间不共享 Ctx
+func (us *UserService) WithContext(ctx context.Context) *UserService {
+	return &UserService{
+		BaseService: us.BaseService.WithContext(ctx),
+		userRepo:    us.userRepo,
+	}
+}
+
 func (us *UserService) Create(req *model.CreateUserRequest) (*model.User, error) {
 	ctx := us.Ctx
 
@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iswangwenbin/gin-starter/internal/service"
+	"go.uber.org/zap"
+)
+
+// metricPrefix Prometheus 指标名前缀
+const metricPrefix = "install_event_worker_"
+
+// StatusServer Worker 的健康检查、状态和指标 HTTP 服务
+type StatusServer struct {
+	worker *InstallEventWorker
+	logger *zap.Logger
+	srv    *http.Server
+}
+
+func NewStatusServer(addr string, worker *InstallEventWorker, logger *zap.Logger) *StatusServer {
+	s := &StatusServer{
+		worker: worker,
+		logger: logger,
+	}
+
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	engine.GET("/healthz", s.healthz)
+	engine.GET("/readyz", s.readyz)
+	engine.GET("/status", s.status)
+	engine.GET("/metrics", s.metrics)
+
+	s.srv = &http.Server{
+		Addr:              addr,
+		Handler:           engine,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Start 在后台监听
+func (s *StatusServer) Start() {
+	go func() {
+		s.logger.Info("Worker status server starting", zap.String("address", s.srv.Addr))
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Worker status server error", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown 停止监听
+func (s *StatusServer) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+// healthz 存活检查，进程能响应即视为存活
+func (s *StatusServer) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readyz 就绪检查，读取循环停滞或熔断器打开时返回 503
+func (s *StatusServer) readyz(c *gin.Context) {
+	if err := s.worker.Ready(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not_ready", "reason": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+func (s *StatusServer) status(c *gin.Context) {
+	status, err := s.worker.GetStatus()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "reason": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// metrics 以 Prometheus 文本格式输出指标，Redis 不可用时省略 Stream 相关指标
+func (s *StatusServer) metrics(c *gin.Context) {
+	var b strings.Builder
+	stats := s.worker.Stats()
+
+	consumer := s.worker.consumer
+	if length, err := consumer.GetStreamLength(); err == nil {
+		writeMetric(&b, "stream_length", "gauge", "Number of entries in the install event stream.", float64(length))
+	}
+	if lag, err := consumer.GetLag(); err == nil {
+		writeMetric(&b, "stream_lag", "gauge", "Entries not yet delivered to the consumer group, -1 if unknown.", float64(lag))
+	}
+	if pending, err := consumer.GetPendingCount(); err == nil {
+		writeMetric(&b, "pending_entries", "gauge", "Entries delivered to the consumer group but not yet acknowledged.", float64(pending))
+	}
+	if length, err := consumer.GetDeadLetterLength(); err == nil {
+		writeMetric(&b, "dead_letter_length", "gauge", "Number of entries in the dead letter stream.", float64(length))
+	}
+
+	writeMetric(&b, "in_flight_messages", "gauge", "Messages read by this process and not yet written or released.", float64(stats.InFlight))
+	writeMetric(&b, "events_written_total", "counter", "Events written to ClickHouse.", float64(stats.EventsWritten))
+	writeMetric(&b, "batches_written_total", "counter", "Batches written to ClickHouse.", float64(stats.BatchesWritten))
+	writeMetric(&b, "write_retries_total", "counter", "Batch write retries after a failed attempt.", float64(stats.WriteRetries))
+	writeMetric(&b, "dead_lettered_total", "counter", "Messages moved to the dead letter stream.", float64(stats.DeadLettered))
+
+	fmt.Fprintf(&b, "# HELP %serrors_total Errors by type.\n# TYPE %serrors_total counter\n", metricPrefix, metricPrefix)
+	for _, e := range []struct {
+		kind  string
+		value int64
+	}{
+		{"read", stats.ReadErrors},
+		{"parse", stats.ParseErrors},
+		{"write", stats.FailedBatches},
+		{"ack", stats.AckErrors},
+		{"probe", stats.ProbeFailures},
+	} {
+		fmt.Fprintf(&b, "%serrors_total{type=%q} %d\n", metricPrefix, e.kind, e.value)
+	}
+
+	lastFlush := 0.0
+	if !stats.LastFlush.IsZero() {
+		lastFlush = float64(stats.LastFlush.UnixMilli()) / 1000
+	}
+	writeMetric(&b, "last_flush_timestamp_seconds", "gauge", "Unix time of the last successful ClickHouse write.", lastFlush)
+
+	breakerOpen := 0.0
+	if stats.BreakerState != service.BreakerClosed {
+		breakerOpen = 1
+	}
+	writeMetric(&b, "circuit_breaker_open", "gauge", "1 while the ClickHouse circuit breaker is open or half-open.", breakerOpen)
+	writeMetric(&b, "circuit_breaker_opens_total", "counter", "Times the ClickHouse circuit breaker opened.", float64(stats.BreakerOpens))
+
+	ready := 1.0
+	if s.worker.Ready() != nil {
+		ready = 0
+	}
+	writeMetric(&b, "ready", "gauge", "1 when the consumer loops are progressing and the breaker is closed.", ready)
+
+	latency := stats.BatchLatency
+	name := metricPrefix + "batch_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Time to write a batch to ClickHouse, including retries.\n# TYPE %s histogram\n", name, name)
+	for i, bound := range latency.Buckets {
+		fmt.Fprintf(&b, "%s_bucket{le=%q} %d\n", name, strconv.FormatFloat(bound, 'f', -1, 64), latency.Counts[i])
+	}
+	fmt.Fprintf(&b, "%s_bucket{le=\"+Inf\"} %d\n", name, latency.Count)
+	fmt.Fprintf(&b, "%s_sum %s\n", name, strconv.FormatFloat(latency.Sum, 'f', -1, 64))
+	fmt.Fprintf(&b, "%s_count %d\n", name, latency.Count)
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string, value float64) {
+	name = metricPrefix + name
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, kind, name, strconv.FormatFloat(value, 'f', -1, 64))
+}
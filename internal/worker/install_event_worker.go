@@ -2,21 +2,28 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/iswangwenbin/gin-starter/internal/model"
 	"github.com/iswangwenbin/gin-starter/internal/repository"
 	"github.com/iswangwenbin/gin-starter/internal/service"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// retentionLockKey 分区保留任务的 Redis 锁
+const retentionLockKey = "install_events:retention:lock"
+
 // InstallEventWorker 安装事件处理工作者
 type InstallEventWorker struct {
 	redis     *redis.Client
 	clickHouse clickhouse.Conn
 	logger    *zap.Logger
 	consumer  *service.InstallEventConsumer
+	retention *service.RetentionService
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
@@ -36,6 +43,7 @@ func NewInstallEventWorker(redis *redis.Client, clickHouse clickhouse.Conn, logg
 		clickHouse: clickHouse,
 		logger:    logger,
 		consumer:  consumer,
+		retention: service.NewRetentionService(installEventRepo, logger),
 		ctx:       ctx,
 		cancel:    cancel,
 	}
@@ -50,11 +58,57 @@ func (w *InstallEventWorker) Start() error {
 		w.logger.Error("Failed to start consumer", zap.Error(err))
 		return err
 	}
+
+	// partition 模式下定期归档并删除过期分区
+	if cfg := w.retention.Config(); cfg.Mode == model.RetentionModePartition && cfg.DefaultDays > 0 {
+		w.wg.Add(1)
+		go w.retentionLoop(cfg.Interval)
+	}
 	
 	w.logger.Info("Install event worker started successfully")
 	return nil
 }
 
+// retentionLoop 启动时及每个周期执行一次分区保留策略，多个 worker 以 Redis 锁保证同一周期只执行一次
+func (w *InstallEventWorker) retentionLoop(interval time.Duration) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.enforceRetention(interval)
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *InstallEventWorker) enforceRetention(interval time.Duration) {
+	// 锁不主动释放，过期前其它 worker 跳过本周期
+	ok, err := w.redis.SetNX(w.ctx, retentionLockKey, w.consumer.Name(), interval).Result()
+	if err != nil {
+		if w.ctx.Err() == nil {
+			w.logger.Error("Failed to acquire retention lock", zap.Error(err))
+		}
+		return
+	}
+	if !ok {
+		return
+	}
+
+	dropped, err := w.retention.EnforcePartitions(w.ctx, time.Now())
+	if len(dropped) > 0 {
+		w.logger.Info("Expired install event partitions dropped", zap.Strings("partitions", dropped))
+	}
+	if err != nil && w.ctx.Err() == nil {
+		w.logger.Error("Failed to enforce install event retention", zap.Error(err))
+	}
+}
+
 // Stop 停止工作者
 func (w *InstallEventWorker) Stop() {
 	w.logger.Info("Stopping install event worker...")
@@ -82,12 +136,91 @@ func (w *InstallEventWorker) GetStatus() (map[string]interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	lag, err := w.consumer.GetLag()
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterLength, err := w.consumer.GetDeadLetterLength()
+	if err != nil {
+		return nil, err
+	}
 	
-	return map[string]interface{}{
-		"pending_count": pendingCount,
-		"stream_length": streamLength,
-		"consumer_group": service.InstallEventConsumerGroup,
-		"consumer_name": service.InstallEventConsumerName,
-		"stream_key": service.InstallEventStreamKey,
-	}, nil
-}
\ No newline at end of file
+	stats := w.consumer.Stats()
+	breaker := map[string]interface{}{
+		"state":          stats.BreakerState,
+		"opens":          stats.BreakerOpens,
+		"probe_failures": stats.ProbeFailures,
+	}
+	if !stats.BreakerOpenedAt.IsZero() {
+		breaker["opened_at"] = stats.BreakerOpenedAt
+	}
+
+	batchLatency := map[string]interface{}{
+		"count":       stats.BatchLatency.Count,
+		"sum_seconds": stats.BatchLatency.Sum,
+	}
+	if stats.BatchLatency.Count > 0 {
+		batchLatency["avg_seconds"] = stats.BatchLatency.Sum / float64(stats.BatchLatency.Count)
+	}
+
+	pipeline := w.consumer.Pipeline()
+	status := map[string]interface{}{
+		"ready":                  w.Ready() == nil,
+		"pending_count":          pendingCount,
+		"stream_length":          streamLength,
+		"stream_lag":             lag,
+		"consumer_group":         pipeline.ConsumerGroup,
+		"consumer_name":          w.consumer.Name(),
+		"stream_key":             pipeline.StreamKey,
+		"batch_size":             pipeline.BatchSize,
+		"read_count":             pipeline.ReadCount,
+		"consumers":              pipeline.Consumers,
+		"writers":                pipeline.Writers,
+		"in_flight":              stats.InFlight,
+		"dead_letter_length":     deadLetterLength,
+		"dead_letter_stream_key": pipeline.DeadLetterKey,
+		"max_delivery_attempts":  service.MaxDeliveryAttempts,
+		"events_written":         stats.EventsWritten,
+		"batches_written":        stats.BatchesWritten,
+		"write_retries":          stats.WriteRetries,
+		"failed_batches":         stats.FailedBatches,
+		"errors": map[string]int64{
+			"read":  stats.ReadErrors,
+			"parse": stats.ParseErrors,
+			"write": stats.FailedBatches,
+			"ack":   stats.AckErrors,
+		},
+		"dead_lettered":   stats.DeadLettered,
+		"batch_latency":   batchLatency,
+		"circuit_breaker": breaker,
+	}
+	if !stats.LastFlush.IsZero() {
+		status["last_flush"] = stats.LastFlush
+	}
+	if !stats.OldestHeartbeat.IsZero() {
+		status["last_loop"] = stats.OldestHeartbeat
+	}
+	return status, nil
+}
+
+// Ready 读取循环停滞或熔断器打开时返回原因
+func (w *InstallEventWorker) Ready() error {
+	stalled, age := w.consumer.Stalled()
+	if stalled && age == 0 {
+		return fmt.Errorf("consumer not started")
+	}
+	if stalled {
+		return fmt.Errorf("consumer loop stalled for %s", age.Truncate(time.Second))
+	}
+	if w.consumer.Stats().BreakerState == service.BreakerOpen {
+		return fmt.Errorf("clickhouse circuit breaker is open")
+	}
+	return nil
+}
+
+// Stats 返回消费者统计
+func (w *InstallEventWorker) Stats() service.InstallEventConsumerStats {
+	return w.consumer.Stats()
+}
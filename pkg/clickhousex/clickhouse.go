@@ -11,10 +11,26 @@ import (
 
 var chConn clickhouse.Conn
 
-// NewClickHouse 初始化 ClickHouse 连接
+// NewClickHouse 初始化 ClickHouse 连接，未启用或无法连接时退出，用于依赖 ClickHouse 的 Worker 和命令
 func NewClickHouse() clickhouse.Conn {
-	cfg := configx.GetConfig()
-	chCfg := cfg.ClickHouse
+	if !configx.GetConfig().ClickHouse.Enabled {
+		log.Fatal("ClickHouse is disabled (clickhouse.enabled is false)")
+	}
+
+	conn, err := Open()
+	if err != nil {
+		log.Fatalf("Failed to connect to ClickHouse: %v", err)
+	}
+	// 测试连接
+	if err := conn.Ping(context.Background()); err != nil {
+		log.Fatalf("Failed to ping ClickHouse: %v", err)
+	}
+	return conn
+}
+
+// Open 创建 ClickHouse 连接池，不检查连通性，连接在首次使用时建立，ClickHouse 恢复后自动可用
+func Open() (clickhouse.Conn, error) {
+	chCfg := configx.GetConfig().ClickHouse
 
 	conn, err := clickhouse.Open(&clickhouse.Options{
 		Addr: []string{chCfg.Addr},
@@ -26,14 +42,10 @@ func NewClickHouse() clickhouse.Conn {
 		DialTimeout: 5 * time.Second,
 	})
 	if err != nil {
-		log.Fatalf("Failed to connect to ClickHouse: %v", err)
-	}
-	// 测试连接
-	if err := conn.Ping(context.Background()); err != nil {
-		log.Fatalf("Failed to ping ClickHouse: %v", err)
+		return nil, err
 	}
 	chConn = conn
-	return conn
+	return conn, nil
 }
 
 // GetClickHouse 获取全局 ClickHouse 连接
@@ -0,0 +1,115 @@
+package clickhousex
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+)
+
+// ExportRequest 通过 HTTP 接口导出查询结果的参数
+type ExportRequest struct {
+	Query    string            // 不含 FORMAT 子句，参数以 {name:Type} 引用
+	Params   map[string]string // 查询参数，由服务端绑定
+	Settings map[string]string // 查询级别的设置
+	Format   string            // ClickHouse 输出格式，如 Parquet、Native、CSVWithNames、JSONEachRow
+	Gzip     bool              // 由 ClickHouse 压缩响应，写入的是 gzip 数据
+	// WaitEndOfQuery 由 ClickHouse 缓冲结果，查询完成后再返回：中途出错时返回错误状态而不是截断的 200 响应，
+	// 响应头中的统计为整个查询的最终值
+	WaitEndOfQuery bool
+}
+
+// ExportResult 导出写入的字节数和 X-ClickHouse-Summary 中的结果行数
+type ExportResult struct {
+	Bytes      int64
+	ResultRows uint64
+	HasRows    bool // 服务端返回了 result_rows，只有 WaitEndOfQuery 时才是最终行数
+}
+
+// Export 通过 HTTP 接口执行查询，将指定格式的结果原样写入 w
+// 原生协议只能返回解析后的行，Parquet 等格式由 ClickHouse 编码
+func Export(ctx context.Context, req ExportRequest, w io.Writer) (*ExportResult, error) {
+	cfg := configx.GetConfig().ClickHouse
+
+	endpoint := cfg.HTTPAddr
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+
+	values := url.Values{}
+	values.Set("database", cfg.Database)
+	for name, value := range req.Params {
+		values.Set("param_"+name, value)
+	}
+	for name, value := range req.Settings {
+		values.Set(name, value)
+	}
+	if req.Gzip {
+		values.Set("enable_http_compression", "1")
+	}
+	if req.WaitEndOfQuery {
+		values.Set("wait_end_of_query", "1")
+	}
+
+	body := strings.NewReader(req.Query + "\nFORMAT " + req.Format)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/?"+values.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("X-ClickHouse-User", cfg.User)
+	httpReq.Header.Set("X-ClickHouse-Key", cfg.Password)
+	if req.Gzip {
+		// 显式设置 Accept-Encoding 后 http.Client 不会自动解压
+		httpReq.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 发送响应头之前出错时 ClickHouse 会设置 X-ClickHouse-Exception-Code
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-ClickHouse-Exception-Code") != "" {
+		var reader io.Reader = resp.Body
+		if resp.Header.Get("Content-Encoding") == "gzip" {
+			if gz, err := gzip.NewReader(resp.Body); err == nil {
+				reader = gz
+			}
+		}
+		message, _ := io.ReadAll(io.LimitReader(reader, 4096))
+		return nil, fmt.Errorf("clickhouse http status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	result := &ExportResult{}
+	result.ResultRows, result.HasRows = summaryResultRows(resp.Header.Get("X-ClickHouse-Summary"))
+	result.Bytes, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return result, fmt.Errorf("clickhouse http read: %w", err)
+	}
+	return result, nil
+}
+
+// summaryResultRows 解析 X-ClickHouse-Summary 中的 result_rows，数值以字符串表示
+func summaryResultRows(header string) (uint64, bool) {
+	var summary map[string]string
+	if header == "" || json.Unmarshal([]byte(header), &summary) != nil {
+		return 0, false
+	}
+	value, ok := summary["result_rows"]
+	if !ok {
+		return 0, false
+	}
+	rows, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return rows, true
+}
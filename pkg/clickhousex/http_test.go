@@ -0,0 +1,27 @@
+package clickhousex
+
+import "testing"
+
+func TestSummaryResultRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		rows   uint64
+		ok     bool
+	}{
+		{name: "result rows", header: `{"read_rows":"120","read_bytes":"4096","result_rows":"100","result_bytes":"2048"}`, rows: 100, ok: true},
+		{name: "zero rows", header: `{"read_rows":"0","result_rows":"0"}`, rows: 0, ok: true},
+		{name: "older server without result rows", header: `{"read_rows":"120","read_bytes":"4096"}`},
+		{name: "missing header", header: ""},
+		{name: "malformed json", header: `{"result_rows":`},
+		{name: "non-numeric", header: `{"result_rows":"many"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, ok := summaryResultRows(tt.header)
+			if rows != tt.rows || ok != tt.ok {
+				t.Errorf("summaryResultRows() = (%d, %v), want (%d, %v)", rows, ok, tt.rows, tt.ok)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package configx
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -18,6 +20,12 @@ type Config struct {
 	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
 	ClickHouse ClickHouseConfig `mapstructure:"clickhouse"`
 	GRPC       GRPCConfig       `mapstructure:"grpc"`
+	GRPCClient GRPCClientConfig `mapstructure:"grpc_client"`
+	AppAuth    AppAuthConfig    `mapstructure:"app_auth"`
+	Ingestion  IngestionConfig  `mapstructure:"ingestion"`
+	Worker     WorkerConfig     `mapstructure:"worker"`
+	Retention  RetentionConfig  `mapstructure:"retention"`
+	Export     ExportConfig     `mapstructure:"export"`
 	Debug      bool             `mapstructure:"debug"`
 }
 
@@ -28,6 +36,16 @@ type ServerConfig struct {
 	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
 	MaxHeaderBytes int           `mapstructure:"max_header_bytes"`
+	TLS            TLSConfig     `mapstructure:"tls"`
+}
+
+// TLSConfig 服务端 TLS 配置，证书文件变化时自动重新加载
+type TLSConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	CertFile          string `mapstructure:"cert_file"`
+	KeyFile           string `mapstructure:"key_file"`
+	ClientCAFile      string `mapstructure:"client_ca_file"`      // 配置后校验客户端证书（mTLS）
+	RequireClientCert bool   `mapstructure:"require_client_cert"` // 为 false 时仅校验客户端提供的证书
 }
 
 type DatabaseConfig struct {
@@ -42,6 +60,8 @@ type DatabaseConfig struct {
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	// RequireMigrations 启动时检查 MySQL 迁移，存在未执行或中断的迁移时拒绝启动
+	RequireMigrations bool `mapstructure:"require_migrations"`
 }
 
 type RedisConfig struct {
@@ -62,9 +82,20 @@ type LogConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string        `mapstructure:"secret"`
-	Expires    time.Duration `mapstructure:"expires"`
-	RefreshTTL time.Duration `mapstructure:"refresh_ttl"`
+	Secret       string         `mapstructure:"secret"`
+	Expires      time.Duration  `mapstructure:"expires"`
+	RefreshTTL   time.Duration  `mapstructure:"refresh_ttl"`
+	Algorithm    string         `mapstructure:"algorithm"`      // HS256 / RS256 / EdDSA
+	SigningKeyID string         `mapstructure:"signing_key_id"` // 当前用于签名的密钥 kid
+	Keys         []JWTKeyConfig `mapstructure:"keys"`           // 非对称密钥，轮换期间新旧密钥同时保留
+}
+
+// JWTKeyConfig 非对称签名密钥，只配置公钥时仅用于验签
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"` // 为空时使用 jwt.algorithm
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
 }
 
 type CORSConfig struct {
@@ -84,20 +115,121 @@ type RateLimitConfig struct {
 // 新增
 
 type ClickHouseConfig struct {
+	Enabled  bool   `mapstructure:"enabled"` // 关闭后 HTTP/gRPC 服务不连接 ClickHouse，事件查询和导出不可用
 	Addr     string `mapstructure:"addr"`
 	Database string `mapstructure:"database"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
+	HTTPAddr string `mapstructure:"http_addr"` // HTTP 接口地址，用于以 Parquet、Native 等格式导出数据
 }
 
 type GRPCConfig struct {
-	Port    int  `mapstructure:"port"`
-	Enabled bool `mapstructure:"enabled"`
+	Port    int       `mapstructure:"port"`
+	Enabled bool      `mapstructure:"enabled"`
+	TLS     TLSConfig `mapstructure:"tls"`
+}
+
+// GRPCClientConfig 调用本服务的 gRPC 客户端配置
+type GRPCClientConfig struct {
+	Target         string          `mapstructure:"target"`          // 如 dns:///gin-starter:50001，配置 addresses 时忽略
+	Addresses      []string        `mapstructure:"addresses"`       // 静态地址列表，轮询负载均衡
+	Timeout        time.Duration   `mapstructure:"timeout"`         // 调用未设置截止时间时的默认超时
+	MaxAttempts    int             `mapstructure:"max_attempts"`    // 幂等方法的最大尝试次数（含首次，上限 5）
+	InitialBackoff time.Duration   `mapstructure:"initial_backoff"` // 首次重试退避
+	MaxBackoff     time.Duration   `mapstructure:"max_backoff"`     // 最大重试退避
+	TLS            ClientTLSConfig `mapstructure:"tls"`
+}
+
+// ClientTLSConfig 客户端 TLS 配置，同时配置证书和私钥时启用 mTLS
+type ClientTLSConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	CAFile     string `mapstructure:"ca_file"`     // 为空时使用系统根证书
+	CertFile   string `mapstructure:"cert_file"`   // mTLS 客户端证书
+	KeyFile    string `mapstructure:"key_file"`    // mTLS 客户端私钥
+	ServerName string `mapstructure:"server_name"` // 覆盖证书校验使用的服务端名称
+}
+
+// AppAuthConfig 事件上报的应用签名认证配置
+type AppAuthConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	ReplayWindow     time.Duration `mapstructure:"replay_window"`      // 允许的时间戳偏差，窗口内相同签名只能使用一次
+	KeyRotationGrace time.Duration `mapstructure:"key_rotation_grace"` // 轮换后旧密钥的保留时间
+	SecretKey        string        `mapstructure:"secret_key"`         // base64 编码的 32 字节 AES-256 密钥，用于加密保存应用密钥
+
+	AllowPlaintextSecrets bool `mapstructure:"allow_plaintext_secrets"` // 未配置 secret_key 时允许明文保存应用密钥，仅用于开发环境
+}
+
+// IngestionConfig 事件写入配置
+type IngestionConfig struct {
+	DedupEnabled bool          `mapstructure:"dedup_enabled"`
+	DedupWindow  time.Duration `mapstructure:"dedup_window"` // 相同 app_id + event_id 在窗口内只入队一次
+}
+
+// RetentionConfig 安装事件保留策略
+// 单个应用的策略以 TTL 规则执行；default_days 在 ttl 模式下同样为 TTL 规则，
+// 在 partition 模式下由 Worker 定期删除早于所有策略的整月分区，archive_dir 不为空时删除前先归档
+type RetentionConfig struct {
+	DefaultDays   int                  `mapstructure:"default_days"`   // 未单独配置的应用保留天数，0 表示永久保留
+	Apps          []AppRetentionConfig `mapstructure:"apps"`           // 按应用配置的保留天数
+	Mode          string               `mapstructure:"mode"`           // ttl 或 partition
+	Interval      time.Duration        `mapstructure:"interval"`       // partition 模式下检查过期分区的间隔
+	ArchiveDir    string               `mapstructure:"archive_dir"`    // 删除分区前的归档目录，为空时不归档
+	ArchiveFormat string               `mapstructure:"archive_format"` // parquet 或 native
+}
+
+// AppRetentionConfig 单个应用的保留天数
+type AppRetentionConfig struct {
+	AppID string `mapstructure:"app_id"`
+	Days  int    `mapstructure:"days"`
 }
 
-var GlobalConfig *Config
+// ExportConfig 安装事件异步导出任务配置
+type ExportConfig struct {
+	Dir           string        `mapstructure:"dir"`            // 导出文件目录，多个实例部署时应为共享存储
+	TTL           time.Duration `mapstructure:"ttl"`            // 任务状态和导出文件的保留时长
+	MaxConcurrent int           `mapstructure:"max_concurrent"` // 每个进程同时执行的导出任务数
+	Timeout       time.Duration `mapstructure:"timeout"`        // 单个导出任务的最长执行时间
+	MaxRange      time.Duration `mapstructure:"max_range"`      // 单次导出允许的最大时间跨度
+}
+
+// WorkerConfig 安装事件 Stream 与 Worker 配置，Stream 相关配置同时用于服务端写入
+type WorkerConfig struct {
+	StreamKey       string        `mapstructure:"stream_key"`
+	DeadLetterKey   string        `mapstructure:"dead_letter_key"` // 死信流，为空时由 stream_key 推导（去掉 _stream 后缀再加 _dead_letter）
+	StreamMaxLen    int64         `mapstructure:"stream_max_len"` // Stream 保留的近似最大长度
+	ConsumerGroup   string        `mapstructure:"consumer_group"`
+	BatchSize       int           `mapstructure:"batch_size"`        // 单批写入 ClickHouse 的事件数
+	BatchTimeout    time.Duration `mapstructure:"batch_timeout"`     // 批次未满时的最长等待时间
+	ReadCount       int64         `mapstructure:"read_count"`        // 单次 XREADGROUP 读取的消息数
+	Consumers       int           `mapstructure:"consumers"`         // 每个进程的读取协程数
+	Writers         int           `mapstructure:"writers"`           // 每个进程并发写入 ClickHouse 的批次数
+	ConsumerName    string        `mapstructure:"consumer_name"`     // 消费者名称，为空时使用 主机名-PID
+	ClaimInterval   time.Duration `mapstructure:"claim_interval"`    // 认领其他消费者滞留消息的间隔
+	ClaimMinIdle    time.Duration `mapstructure:"claim_min_idle"`    // 待确认消息空闲超过该时长才会被认领
+	ConsumerMaxIdle time.Duration `mapstructure:"consumer_max_idle"` // 没有待确认消息且空闲超过该时长的消费者会被删除
+	HTTPAddr        string        `mapstructure:"http_addr"`         // 健康检查、状态和指标的监听地址，为空时不启动
+	StallTimeout    time.Duration `mapstructure:"stall_timeout"`     // 读取循环超过该时长没有推进时就绪检查失败
+}
+
+var (
+	GlobalConfig *Config
+	configFile   string
+)
 
+// Load 读取配置文件并设置为全局配置
 func Load(configPath string) (*Config, error) {
+	config, err := ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	GlobalConfig = config
+	configFile = configPath
+	return config, nil
+}
+
+// ReadFile 读取、展开并校验配置文件，不修改全局配置，用于运行时重新加载
+func ReadFile(configPath string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigType("yaml")
 	v.SetConfigFile(configPath)
@@ -112,6 +244,9 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// 展开配置值中的 ${VAR} 环境变量引用
+	expandEnv(v)
+
 	// 调试：打印 Viper 读取的原始值
 	fmt.Printf("Viper raw values:\n")
 	fmt.Printf("  database.password: %v\n", v.Get("database.password"))
@@ -132,10 +267,38 @@ func Load(configPath string) (*Config, error) {
 		config.Database.Name,
 	)
 
-	GlobalConfig = &config
+	if err := config.ValidateAndWarn(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &config, nil
 }
 
+// expandEnv 将字符串及字符串列表中的 ${VAR} 替换为环境变量的值
+func expandEnv(v *viper.Viper) {
+	for _, key := range v.AllKeys() {
+		switch val := v.Get(key).(type) {
+		case string:
+			if strings.Contains(val, "${") {
+				v.Set(key, os.ExpandEnv(val))
+			}
+		case []interface{}:
+			expanded := make([]interface{}, len(val))
+			changed := false
+			for i, item := range val {
+				expanded[i] = item
+				if str, ok := item.(string); ok && strings.Contains(str, "${") {
+					expanded[i] = os.ExpandEnv(str)
+					changed = true
+				}
+			}
+			if changed {
+				v.Set(key, expanded)
+			}
+		}
+	}
+}
+
 func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.host", "0.0.0.0")
@@ -144,6 +307,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.read_timeout", "60s")
 	v.SetDefault("server.write_timeout", "60s")
 	v.SetDefault("server.max_header_bytes", 1<<20) // 1MB
+	v.SetDefault("server.tls.enabled", false)
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -157,6 +321,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_idle_conns", 10)
 	v.SetDefault("database.max_open_conns", 100)
 	v.SetDefault("database.conn_max_lifetime", "3600s")
+	v.SetDefault("database.require_migrations", false)
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -177,6 +342,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("jwt.secret", "your-secret-key")
 	v.SetDefault("jwt.expires", "24h")
 	v.SetDefault("jwt.refresh_ttl", "168h") // 7 days
+	v.SetDefault("jwt.algorithm", "HS256")
 
 	// CORS defaults
 	v.SetDefault("cors.allowed_origins", []string{"*"})
@@ -190,14 +356,67 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("rate_limit.enabled", false)
 
 	// ClickHouse defaults
+	v.SetDefault("clickhouse.enabled", true)
 	v.SetDefault("clickhouse.addr", "127.0.0.1:9000")
 	v.SetDefault("clickhouse.database", "default")
 	v.SetDefault("clickhouse.user", "default")
 	v.SetDefault("clickhouse.password", "")
+	v.SetDefault("clickhouse.http_addr", "127.0.0.1:8123")
+
+	// Retention defaults
+	v.SetDefault("retention.default_days", 0)
+	v.SetDefault("retention.mode", "ttl")
+	v.SetDefault("retention.interval", "24h")
+	v.SetDefault("retention.archive_dir", "")
+	v.SetDefault("retention.archive_format", "parquet")
+
+	// Export defaults
+	v.SetDefault("export.dir", "exports")
+	v.SetDefault("export.ttl", "24h")
+	v.SetDefault("export.max_concurrent", 2)
+	v.SetDefault("export.timeout", "30m")
+	v.SetDefault("export.max_range", "8784h")
 
 	// gRPC defaults
 	v.SetDefault("grpc.port", 9090)
 	v.SetDefault("grpc.enabled", true)
+	v.SetDefault("grpc.tls.enabled", false)
+
+	// gRPC client defaults
+	v.SetDefault("grpc_client.target", "dns:///localhost:9090")
+	v.SetDefault("grpc_client.timeout", "5s")
+	v.SetDefault("grpc_client.max_attempts", 3)
+	v.SetDefault("grpc_client.initial_backoff", "100ms")
+	v.SetDefault("grpc_client.max_backoff", "2s")
+	v.SetDefault("grpc_client.tls.enabled", false)
+
+	// App auth defaults
+	v.SetDefault("app_auth.enabled", true)
+	v.SetDefault("app_auth.replay_window", "5m")
+	v.SetDefault("app_auth.key_rotation_grace", "24h")
+	v.SetDefault("app_auth.secret_key", "")
+	v.SetDefault("app_auth.allow_plaintext_secrets", false)
+
+	// Ingestion defaults
+	v.SetDefault("ingestion.dedup_enabled", true)
+	v.SetDefault("ingestion.dedup_window", "24h")
+
+	// Worker defaults
+	v.SetDefault("worker.stream_key", "install_events_stream")
+	v.SetDefault("worker.dead_letter_key", "")
+	v.SetDefault("worker.stream_max_len", 100000)
+	v.SetDefault("worker.consumer_group", "install_events_consumer_group")
+	v.SetDefault("worker.batch_size", 100)
+	v.SetDefault("worker.batch_timeout", "5s")
+	v.SetDefault("worker.read_count", 10)
+	v.SetDefault("worker.consumers", 1)
+	v.SetDefault("worker.writers", 1)
+	v.SetDefault("worker.consumer_name", "")
+	v.SetDefault("worker.claim_interval", "30s")
+	v.SetDefault("worker.claim_min_idle", "5m")
+	v.SetDefault("worker.consumer_max_idle", "24h")
+	v.SetDefault("worker.http_addr", ":8090")
+	v.SetDefault("worker.stall_timeout", "2m")
 
 	// Debug defaults
 	v.SetDefault("debug", false)
@@ -207,6 +426,11 @@ func GetConfig() *Config {
 	return GlobalConfig
 }
 
+// ConfigFile 返回全局配置对应的配置文件路径
+func ConfigFile() string {
+	return configFile
+}
+
 func (c *Config) GetServerAddress() string {
 	return c.Server.Host + ":" + c.Server.Port
 }
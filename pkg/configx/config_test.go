@@ -0,0 +1,108 @@
+package configx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testBaseConfig = `
+database:
+  password: "secret"
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testBaseConfig+content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadExpandsEnv(t *testing.T) {
+	t.Setenv("TEST_JWT_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("TEST_FRONTEND_URL", "https://example.com")
+
+	cfg, err := Load(writeConfig(t, `
+server:
+  mode: release
+jwt:
+  secret: "${TEST_JWT_SECRET}"
+cors:
+  allowed_origins:
+    - "${TEST_FRONTEND_URL}"
+app_auth:
+  allow_plaintext_secrets: true
+`))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.JWT.Secret != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("jwt secret = %q, want expanded value", cfg.JWT.Secret)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.CORS.AllowedOrigins[0] != "https://example.com" {
+		t.Errorf("allowed origins = %v, want [https://example.com]", cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "unset jwt secret",
+			content: `
+jwt:
+  secret: "${TEST_UNSET_JWT_SECRET}"
+app_auth:
+  allow_plaintext_secrets: true
+`,
+			wantErr: "jwt secret cannot be empty",
+		},
+		{
+			name: "unsafe jwt secret in release mode",
+			content: `
+server:
+  mode: release
+jwt:
+  secret: "development-secret-key-change-in-production"
+app_auth:
+  allow_plaintext_secrets: true
+`,
+			wantErr: "unsafe default value",
+		},
+		{
+			name: "invalid app auth secret key",
+			content: `
+jwt:
+  secret: "0123456789abcdef0123456789abcdef"
+app_auth:
+  secret_key: "not-a-key"
+`,
+			wantErr: "app_auth secret_key",
+		},
+		{
+			name: "app auth secret key missing without plaintext opt-in",
+			content: `
+jwt:
+  secret: "0123456789abcdef0123456789abcdef"
+app_auth:
+  allow_plaintext_secrets: false
+`,
+			wantErr: "app_auth secret_key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
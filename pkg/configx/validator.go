@@ -6,6 +6,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/iswangwenbin/gin-starter/pkg/cryptox"
 )
 
 // Validate 验证配置的有效性
@@ -29,6 +31,22 @@ func (c *Config) Validate() error {
 	if err := c.validateLog(); err != nil {
 		return fmt.Errorf("log config validation failed: %w", err)
 	}
+
+	if err := c.validateAppAuth(); err != nil {
+		return fmt.Errorf("app auth config validation failed: %w", err)
+	}
+
+	if err := c.validateWorker(); err != nil {
+		return fmt.Errorf("worker config validation failed: %w", err)
+	}
+
+	if err := c.validateRetention(); err != nil {
+		return fmt.Errorf("retention config validation failed: %w", err)
+	}
+
+	if err := c.validateExport(); err != nil {
+		return fmt.Errorf("export config validation failed: %w", err)
+	}
 	
 	return nil
 }
@@ -57,10 +75,34 @@ func (c *Config) validateServer() error {
 	if c.Server.MaxHeaderBytes <= 0 {
 		return errors.New("server max header bytes must be positive")
 	}
+
+	if err := c.Server.TLS.validate(); err != nil {
+		return fmt.Errorf("server %w", err)
+	}
+
+	if err := c.GRPC.TLS.validate(); err != nil {
+		return fmt.Errorf("grpc %w", err)
+	}
 	
 	return nil
 }
 
+func (t TLSConfig) validate() error {
+	if !t.Enabled {
+		return nil
+	}
+
+	if t.CertFile == "" || t.KeyFile == "" {
+		return errors.New("tls cert_file and key_file are required when tls is enabled")
+	}
+
+	if t.RequireClientCert && t.ClientCAFile == "" {
+		return errors.New("tls client_ca_file is required when require_client_cert is enabled")
+	}
+
+	return nil
+}
+
 func (c *Config) validateDatabase() error {
 	if c.Database.Host == "" {
 		return errors.New("database host cannot be empty")
@@ -122,11 +164,40 @@ func (c *Config) validateRedis() error {
 }
 
 func (c *Config) validateJWT() error {
+	switch c.JWT.Algorithm {
+	case "", "HS256":
+		if err := c.validateJWTSecret(); err != nil {
+			return err
+		}
+	case "RS256", "EdDSA":
+		if err := c.validateJWTKeys(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported jwt algorithm: %s", c.JWT.Algorithm)
+	}
+	
+	if c.JWT.Expires <= 0 {
+		return errors.New("jwt expires duration must be positive")
+	}
+	
+	if c.JWT.RefreshTTL <= 0 {
+		return errors.New("jwt refresh ttl must be positive")
+	}
+	
+	if c.JWT.RefreshTTL <= c.JWT.Expires {
+		return errors.New("jwt refresh ttl should be longer than expires duration")
+	}
+	
+	return nil
+}
+
+func (c *Config) validateJWTSecret() error {
 	if c.JWT.Secret == "" {
 		return errors.New("jwt secret cannot be empty")
 	}
 	
-	// 检查是否使用了默认的不安全密钥
+	// 检查是否使用了默认的不安全密钥，仅在 release 模式下拒绝，开发环境由 printWarnings 提示
 	unsafeSecrets := []string{
 		"your-secret-key",
 		"secret",
@@ -135,7 +206,7 @@ func (c *Config) validateJWT() error {
 	}
 	
 	for _, unsafe := range unsafeSecrets {
-		if c.JWT.Secret == unsafe {
+		if c.JWT.Secret == unsafe && c.Server.Mode == "release" {
 			return fmt.Errorf("jwt secret is using unsafe default value: %s", unsafe)
 		}
 	}
@@ -144,16 +215,47 @@ func (c *Config) validateJWT() error {
 		return errors.New("jwt secret should be at least 32 characters long")
 	}
 	
-	if c.JWT.Expires <= 0 {
-		return errors.New("jwt expires duration must be positive")
+	return nil
+}
+
+func (c *Config) validateJWTKeys() error {
+	if len(c.JWT.Keys) == 0 {
+		return errors.New("jwt keys cannot be empty for asymmetric algorithms")
 	}
 	
-	if c.JWT.RefreshTTL <= 0 {
-		return errors.New("jwt refresh ttl must be positive")
+	if c.JWT.SigningKeyID == "" {
+		return errors.New("jwt signing key id cannot be empty")
 	}
 	
-	if c.JWT.RefreshTTL <= c.JWT.Expires {
-		return errors.New("jwt refresh ttl should be longer than expires duration")
+	seen := make(map[string]bool)
+	hasSigningKey := false
+	for _, key := range c.JWT.Keys {
+		if key.ID == "" {
+			return errors.New("jwt key kid cannot be empty")
+		}
+		if seen[key.ID] {
+			return fmt.Errorf("duplicate jwt key kid: %s", key.ID)
+		}
+		seen[key.ID] = true
+		
+		if key.Algorithm != "" && key.Algorithm != "RS256" && key.Algorithm != "EdDSA" {
+			return fmt.Errorf("unsupported algorithm for jwt key %s: %s", key.ID, key.Algorithm)
+		}
+		
+		if key.PrivateKeyFile == "" && key.PublicKeyFile == "" {
+			return fmt.Errorf("jwt key %s must have a private or public key file", key.ID)
+		}
+		
+		if key.ID == c.JWT.SigningKeyID {
+			if key.PrivateKeyFile == "" {
+				return fmt.Errorf("jwt signing key %s must have a private key file", key.ID)
+			}
+			hasSigningKey = true
+		}
+	}
+	
+	if !hasSigningKey {
+		return fmt.Errorf("jwt signing key %s not found in keys", c.JWT.SigningKeyID)
 	}
 	
 	return nil
@@ -247,6 +349,81 @@ func (c *Config) validateRateLimit() error {
 	return nil
 }
 
+func (c *Config) validateWorker() error {
+	w := c.Worker
+	if w.StreamMaxLen < 0 || w.BatchSize < 0 || w.ReadCount < 0 || w.Consumers < 0 || w.Writers < 0 {
+		return errors.New("worker stream_max_len, batch_size, read_count, consumers and writers must not be negative")
+	}
+	if w.BatchTimeout < 0 {
+		return errors.New("worker batch_timeout must not be negative")
+	}
+	if w.BatchSize > 0 && w.ReadCount > int64(w.BatchSize) {
+		return errors.New("worker read_count must not exceed batch_size")
+	}
+	if w.DeadLetterKey != "" && w.DeadLetterKey == w.StreamKey {
+		return errors.New("worker dead_letter_key must differ from stream_key")
+	}
+	return nil
+}
+
+func (c *Config) validateRetention() error {
+	r := c.Retention
+	if r.DefaultDays < 0 {
+		return errors.New("retention default_days must not be negative")
+	}
+	if r.Mode != "ttl" && r.Mode != "partition" {
+		return fmt.Errorf("retention mode must be ttl or partition, got %q", r.Mode)
+	}
+	if r.ArchiveFormat != "parquet" && r.ArchiveFormat != "native" {
+		return fmt.Errorf("retention archive_format must be parquet or native, got %q", r.ArchiveFormat)
+	}
+	if r.Mode == "partition" && r.Interval <= 0 {
+		return errors.New("retention interval must be positive in partition mode")
+	}
+
+	seen := make(map[string]bool, len(r.Apps))
+	for _, app := range r.Apps {
+		if app.AppID == "" || app.Days <= 0 {
+			return errors.New("retention apps require an app_id and positive days")
+		}
+		if seen[app.AppID] {
+			return fmt.Errorf("retention for app %q is configured more than once", app.AppID)
+		}
+		seen[app.AppID] = true
+	}
+	return nil
+}
+
+func (c *Config) validateExport() error {
+	e := c.Export
+	if e.Dir == "" {
+		return errors.New("export dir cannot be empty")
+	}
+	if e.TTL <= 0 || e.Timeout <= 0 || e.MaxRange <= 0 {
+		return errors.New("export ttl, timeout and max_range must be positive")
+	}
+	if e.MaxConcurrent <= 0 {
+		return errors.New("export max_concurrent must be positive")
+	}
+	// 任务状态在 ttl 后过期，执行时间超过 ttl 的任务完成时状态已不存在
+	if e.Timeout >= e.TTL {
+		return errors.New("export timeout must be shorter than ttl")
+	}
+	return nil
+}
+
+func (c *Config) validateAppAuth() error {
+	if _, err := cryptox.NewCipher(c.AppAuth.SecretKey); err != nil {
+		return fmt.Errorf("app_auth secret_key: %w", err)
+	}
+
+	// 明文保存应用密钥必须显式开启
+	if c.AppAuth.Enabled && c.AppAuth.SecretKey == "" && !c.AppAuth.AllowPlaintextSecrets {
+		return errors.New("app_auth secret_key is required unless allow_plaintext_secrets is enabled")
+	}
+	return nil
+}
+
 // ValidateAndWarn 验证配置并输出警告
 func (c *Config) ValidateAndWarn() error {
 	if err := c.Validate(); err != nil {
@@ -270,6 +447,11 @@ func (c *Config) printWarnings() {
 		fmt.Println("⚠️  WARNING: JWT secret contains 'development'. Please use a secure secret in production!")
 	}
 	
+	// 应用密钥加密警告
+	if c.AppAuth.SecretKey == "" && c.AppAuth.AllowPlaintextSecrets {
+		fmt.Println("⚠️  WARNING: app_auth.allow_plaintext_secrets is enabled. App key secrets will be stored in plaintext!")
+	}
+	
 	// 数据库密码警告
 	if c.Database.Password == "" {
 		fmt.Println("⚠️  WARNING: Database password is empty. This is not recommended for production!")
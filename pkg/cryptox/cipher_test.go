@@ -0,0 +1,80 @@
+package cryptox
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestCipherRoundTrip(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewCipher(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", 32))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := c.Encrypt("secret-value", "ak_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "secret-value") {
+		t.Fatalf("Encrypt() = %q, want ciphertext", encrypted)
+	}
+
+	tests := []struct {
+		name    string
+		cipher  *Cipher
+		value   string
+		aad     string
+		want    string
+		wantErr bool
+	}{
+		{name: "round trip", cipher: c, value: encrypted, aad: "ak_1", want: "secret-value"},
+		{name: "other aad", cipher: c, value: encrypted, aad: "ak_2", wantErr: true},
+		{name: "other key", cipher: other, value: encrypted, aad: "ak_1", wantErr: true},
+		{name: "no key configured", cipher: nil, value: encrypted, aad: "ak_1", wantErr: true},
+		{name: "legacy plaintext", cipher: c, value: "plain", aad: "ak_1", want: "plain"},
+		{name: "legacy plaintext without key", cipher: nil, value: "plain", aad: "ak_1", want: "plain"},
+		{name: "truncated", cipher: c, value: encryptedPrefix + "AAAA", aad: "ak_1", wantErr: true},
+		{name: "malformed base64", cipher: c, value: encryptedPrefix + "!!", aad: "ak_1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cipher.Decrypt(tt.value, tt.aad)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Decrypt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCipher(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "empty disables encryption", key: "", wantNil: true},
+		{name: "32 bytes", key: base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		{name: "16 bytes", key: base64.StdEncoding.EncodeToString(make([]byte, 16)), wantErr: true},
+		{name: "not base64", key: "not base64!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCipher(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCipher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (c == nil) != tt.wantNil {
+				t.Errorf("NewCipher() = %v, want nil %v", c, tt.wantNil)
+			}
+		})
+	}
+}
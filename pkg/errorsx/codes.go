@@ -39,12 +39,21 @@ const (
 	CodeTokenInvalid        ErrorCode = 2002
 	CodeTokenMissing        ErrorCode = 2003
 	CodeInsufficientPermission ErrorCode = 2004
+	CodeRefreshTokenInvalid    ErrorCode = 2005
+	CodeRefreshTokenReused     ErrorCode = 2006
+	CodeAppNotFound            ErrorCode = 2007
+	CodeAppDisabled            ErrorCode = 2008
+	CodeAPIKeyInvalid          ErrorCode = 2009
+	CodeSignatureInvalid       ErrorCode = 2010
+	CodeSignatureExpired       ErrorCode = 2011
+	CodeSignatureReplayed      ErrorCode = 2012
 
 	// 数据验证错误 (3000-3999)
 	CodeValidationFailed    ErrorCode = 3001
 	CodeRequiredFieldMissing ErrorCode = 3002
 	CodeInvalidFormat       ErrorCode = 3003
 	CodeValueOutOfRange     ErrorCode = 3004
+	CodeDuplicateEvent      ErrorCode = 3005
 
 	// 外部服务错误 (4000-4999)
 	CodeDatabaseError       ErrorCode = 4001
@@ -78,8 +87,14 @@ func (code ErrorCode) GetHTTPStatus() int {
 			return 400
 		}
 	case code >= 2000 && code < 3000: // 认证授权错误
+		if code == CodeInsufficientPermission || code == CodeAppDisabled {
+			return 403
+		}
 		return 401
 	case code >= 3000 && code < 4000: // 数据验证错误
+		if code == CodeDuplicateEvent {
+			return 409
+		}
 		return 400
 	case code >= 4000 && code < 5000: // 外部服务错误
 		return 500
@@ -120,12 +135,21 @@ func (code ErrorCode) GetMessage() string {
 		CodeTokenInvalid:           "Invalid token",
 		CodeTokenMissing:           "Authorization token is missing",
 		CodeInsufficientPermission: "Insufficient permission",
+		CodeRefreshTokenInvalid:    "Invalid refresh token",
+		CodeRefreshTokenReused:     "Refresh token reuse detected",
+		CodeAppNotFound:            "Unknown app",
+		CodeAppDisabled:            "App is disabled",
+		CodeAPIKeyInvalid:          "Invalid API key",
+		CodeSignatureInvalid:       "Invalid request signature",
+		CodeSignatureExpired:       "Request timestamp is outside the allowed window",
+		CodeSignatureReplayed:      "Request signature has already been used",
 
 		// 数据验证错误
 		CodeValidationFailed:     "Validation failed",
 		CodeRequiredFieldMissing: "Required field is missing",
 		CodeInvalidFormat:        "Invalid format",
 		CodeValueOutOfRange:      "Value is out of range",
+		CodeDuplicateEvent:       "Event has already been received",
 
 		// 外部服务错误
 		CodeDatabaseError:      "Database error",
@@ -142,4 +166,67 @@ func (code ErrorCode) GetMessage() string {
 		return msg
 	}
 	return "Unknown error"
-}
\ No newline at end of file
+}
+
+// GetReason 获取错误代码稳定的 UPPER_SNAKE_CASE 名称，用作 gRPC ErrorInfo.Reason，不随消息文案变化
+func (code ErrorCode) GetReason() string {
+	reasons := map[ErrorCode]string{
+		// HTTP 状态码
+		CodeSuccess:             "SUCCESS",
+		CodeBadRequest:          "BAD_REQUEST",
+		CodeUnauthorized:        "UNAUTHORIZED",
+		CodeForbidden:           "FORBIDDEN",
+		CodeNotFound:            "NOT_FOUND",
+		CodeMethodNotAllowed:    "METHOD_NOT_ALLOWED",
+		CodeConflict:            "CONFLICT",
+		CodeUnprocessableEntity: "UNPROCESSABLE_ENTITY",
+		CodeTooManyRequests:     "TOO_MANY_REQUESTS",
+		CodeInternalServerError: "INTERNAL_SERVER_ERROR",
+		CodeServiceUnavailable:  "SERVICE_UNAVAILABLE",
+
+		// 用户相关错误
+		CodeUserNotFound:       "USER_NOT_FOUND",
+		CodeUserAlreadyExists:  "USER_ALREADY_EXISTS",
+		CodeInvalidCredentials: "INVALID_CREDENTIALS",
+		CodeUserDisabled:       "USER_DISABLED",
+		CodePasswordTooWeak:    "PASSWORD_TOO_WEAK",
+		CodeInvalidEmail:       "INVALID_EMAIL",
+		CodeInvalidPhone:       "INVALID_PHONE",
+
+		// 认证授权错误
+		CodeTokenExpired:           "TOKEN_EXPIRED",
+		CodeTokenInvalid:           "TOKEN_INVALID",
+		CodeTokenMissing:           "TOKEN_MISSING",
+		CodeInsufficientPermission: "INSUFFICIENT_PERMISSION",
+		CodeRefreshTokenInvalid:    "REFRESH_TOKEN_INVALID",
+		CodeRefreshTokenReused:     "REFRESH_TOKEN_REUSED",
+		CodeAppNotFound:            "APP_NOT_FOUND",
+		CodeAppDisabled:            "APP_DISABLED",
+		CodeAPIKeyInvalid:          "API_KEY_INVALID",
+		CodeSignatureInvalid:       "SIGNATURE_INVALID",
+		CodeSignatureExpired:       "SIGNATURE_EXPIRED",
+		CodeSignatureReplayed:      "SIGNATURE_REPLAYED",
+
+		// 数据验证错误
+		CodeValidationFailed:     "VALIDATION_FAILED",
+		CodeRequiredFieldMissing: "REQUIRED_FIELD_MISSING",
+		CodeInvalidFormat:        "INVALID_FORMAT",
+		CodeValueOutOfRange:      "VALUE_OUT_OF_RANGE",
+		CodeDuplicateEvent:       "DUPLICATE_EVENT",
+
+		// 外部服务错误
+		CodeDatabaseError:      "DATABASE_ERROR",
+		CodeRedisError:         "REDIS_ERROR",
+		CodeThirdPartyAPIError: "THIRD_PARTY_API_ERROR",
+
+		// 系统错误
+		CodeConfigError:     "CONFIG_ERROR",
+		CodeFileSystemError: "FILE_SYSTEM_ERROR",
+		CodeNetworkError:    "NETWORK_ERROR",
+	}
+
+	if reason, exists := reasons[code]; exists {
+		return reason
+	}
+	return "UNKNOWN"
+}
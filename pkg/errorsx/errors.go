@@ -102,7 +102,16 @@ var (
 	ErrTokenInvalid        = New(CodeTokenInvalid, CodeTokenInvalid.GetMessage())
 	ErrTokenMissing        = New(CodeTokenMissing, CodeTokenMissing.GetMessage())
 	ErrInsufficientPermission = New(CodeInsufficientPermission, CodeInsufficientPermission.GetMessage())
+	ErrRefreshTokenInvalid = New(CodeRefreshTokenInvalid, CodeRefreshTokenInvalid.GetMessage())
+	ErrRefreshTokenReused  = New(CodeRefreshTokenReused, CodeRefreshTokenReused.GetMessage())
+	ErrAppNotFound         = New(CodeAppNotFound, CodeAppNotFound.GetMessage())
+	ErrAppDisabled         = New(CodeAppDisabled, CodeAppDisabled.GetMessage())
+	ErrAPIKeyInvalid       = New(CodeAPIKeyInvalid, CodeAPIKeyInvalid.GetMessage())
+	ErrSignatureInvalid    = New(CodeSignatureInvalid, CodeSignatureInvalid.GetMessage())
+	ErrSignatureExpired    = New(CodeSignatureExpired, CodeSignatureExpired.GetMessage())
+	ErrSignatureReplayed   = New(CodeSignatureReplayed, CodeSignatureReplayed.GetMessage())
 	ErrValidationFailed    = New(CodeValidationFailed, CodeValidationFailed.GetMessage())
+	ErrDuplicateEvent      = New(CodeDuplicateEvent, CodeDuplicateEvent.GetMessage())
 	ErrDatabaseError       = New(CodeDatabaseError, CodeDatabaseError.GetMessage())
 	ErrRedisError          = New(CodeRedisError, CodeRedisError.GetMessage())
 )
\ No newline at end of file
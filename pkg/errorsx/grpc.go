@@ -0,0 +1,180 @@
+package errorsx
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain gRPC ErrorInfo 中标识本服务错误的 domain
+const ErrorDomain = "gin-starter"
+
+// ErrorInfo metadata 键
+const (
+	metadataCode    = "code"
+	metadataDetails = "details"
+)
+
+// GetGRPCCode 获取错误代码对应的 gRPC 状态码，按 HTTP 状态码映射以保持两种协议一致
+func (code ErrorCode) GetGRPCCode() codes.Code {
+	switch code.GetHTTPStatus() {
+	case 200:
+		return codes.OK
+	case 400, 422:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 405:
+		return codes.Unimplemented
+	case 409:
+		return codes.AlreadyExists
+	case 429:
+		return codes.ResourceExhausted
+	case 503:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// GetGRPCStatus 转换为 gRPC 状态，错误名称写入 ErrorInfo.Reason、数字代码写入 metadata，字段校验错误写入 BadRequest
+func (e *AppError) GetGRPCStatus() *status.Status {
+	st := status.New(e.Code.GetGRPCCode(), e.Message)
+
+	info := &errdetails.ErrorInfo{
+		Reason:   e.Code.GetReason(),
+		Domain:   ErrorDomain,
+		Metadata: map[string]string{metadataCode: strconv.Itoa(int(e.Code))},
+	}
+
+	var badRequest *errdetails.BadRequest
+	switch details := e.Details.(type) {
+	case nil:
+	case map[string]string:
+		badRequest = &errdetails.BadRequest{}
+		fields := make([]string, 0, len(details))
+		for field := range details {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: details[field],
+			})
+		}
+	default:
+		if data, err := json.Marshal(details); err == nil {
+			info.Metadata[metadataDetails] = string(data)
+		}
+	}
+
+	details := []protoadapt.MessageV1{info}
+	if badRequest != nil {
+		details = append(details, badRequest)
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// ToGRPC 将错误转换为 gRPC 错误，已是 gRPC 状态的错误原样返回
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.GetGRPCStatus().Err()
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// FromGRPC 将 gRPC 错误还原为 *AppError，便于跨网络使用 IsCode 判断
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return NewWithError(CodeNetworkError, CodeNetworkError.GetMessage(), err)
+	}
+
+	appErr := &AppError{
+		Code:    codeFromGRPC(st.Code()),
+		Message: st.Message(),
+		Err:     err,
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.Domain != ErrorDomain {
+				continue
+			}
+			if code, err := strconv.Atoi(d.Metadata[metadataCode]); err == nil {
+				appErr.Code = ErrorCode(code)
+			}
+			if raw, ok := d.Metadata[metadataDetails]; ok {
+				var details interface{}
+				if err := json.Unmarshal([]byte(raw), &details); err == nil {
+					appErr.Details = details
+				}
+			}
+		case *errdetails.BadRequest:
+			violations := make(map[string]string, len(d.FieldViolations))
+			for _, v := range d.FieldViolations {
+				violations[v.Field] = v.Description
+			}
+			appErr.Details = violations
+		}
+	}
+
+	return appErr
+}
+
+// codeFromGRPC 没有 ErrorInfo 时按 gRPC 状态码推断错误代码
+func codeFromGRPC(code codes.Code) ErrorCode {
+	switch code {
+	case codes.OK:
+		return CodeSuccess
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return CodeBadRequest
+	case codes.Unauthenticated:
+		return CodeUnauthorized
+	case codes.PermissionDenied:
+		return CodeForbidden
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return CodeConflict
+	case codes.ResourceExhausted:
+		return CodeTooManyRequests
+	case codes.Unimplemented:
+		return CodeMethodNotAllowed
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternalServerError
+	}
+}
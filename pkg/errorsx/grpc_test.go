@@ -0,0 +1,160 @@
+package errorsx
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetGRPCCode(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want codes.Code
+	}{
+		{CodeSuccess, codes.OK},
+		{CodeBadRequest, codes.InvalidArgument},
+		{CodeUnprocessableEntity, codes.InvalidArgument},
+		{CodeValidationFailed, codes.InvalidArgument},
+		{CodeUnauthorized, codes.Unauthenticated},
+		{CodeInvalidCredentials, codes.Unauthenticated},
+		{CodeSignatureInvalid, codes.Unauthenticated},
+		{CodeForbidden, codes.PermissionDenied},
+		{CodeAppDisabled, codes.PermissionDenied},
+		{CodeUserNotFound, codes.NotFound},
+		{CodeMethodNotAllowed, codes.Unimplemented},
+		{CodeUserAlreadyExists, codes.AlreadyExists},
+		{CodeDuplicateEvent, codes.AlreadyExists},
+		{CodeTooManyRequests, codes.ResourceExhausted},
+		{CodeServiceUnavailable, codes.Unavailable},
+		{CodeDatabaseError, codes.Internal},
+		{CodeConfigError, codes.Internal},
+		{ErrorCode(9999), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.GetReason(), func(t *testing.T) {
+			if got := tt.code.GetGRPCCode(); got != tt.want {
+				t.Errorf("GetGRPCCode(%d) = %s, want %s", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCErrorInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *AppError
+		wantReason string
+		wantCode   codes.Code
+	}{
+		{name: "http code", err: New(CodeNotFound, "missing"), wantReason: "NOT_FOUND", wantCode: codes.NotFound},
+		{name: "business code", err: ErrSignatureReplayed, wantReason: "SIGNATURE_REPLAYED", wantCode: codes.Unauthenticated},
+		{name: "unknown code", err: New(ErrorCode(9999), "boom"), wantReason: "UNKNOWN", wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := tt.err.GetGRPCStatus()
+			if st.Code() != tt.wantCode {
+				t.Errorf("code = %s, want %s", st.Code(), tt.wantCode)
+			}
+			var info *errdetails.ErrorInfo
+			for _, detail := range st.Details() {
+				if d, ok := detail.(*errdetails.ErrorInfo); ok {
+					info = d
+				}
+			}
+			if info == nil {
+				t.Fatal("ErrorInfo missing")
+			}
+			if info.Reason != tt.wantReason || info.Domain != ErrorDomain {
+				t.Errorf("ErrorInfo = %s/%s, want %s/%s", info.Domain, info.Reason, ErrorDomain, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGetReasonStable(t *testing.T) {
+	all := []ErrorCode{
+		CodeSuccess, CodeBadRequest, CodeUnauthorized, CodeForbidden, CodeNotFound, CodeMethodNotAllowed,
+		CodeConflict, CodeUnprocessableEntity, CodeTooManyRequests, CodeInternalServerError, CodeServiceUnavailable,
+		CodeUserNotFound, CodeUserAlreadyExists, CodeInvalidCredentials, CodeUserDisabled, CodePasswordTooWeak,
+		CodeInvalidEmail, CodeInvalidPhone, CodeTokenExpired, CodeTokenInvalid, CodeTokenMissing,
+		CodeInsufficientPermission, CodeRefreshTokenInvalid, CodeRefreshTokenReused, CodeAppNotFound, CodeAppDisabled,
+		CodeAPIKeyInvalid, CodeSignatureInvalid, CodeSignatureExpired, CodeSignatureReplayed, CodeValidationFailed,
+		CodeRequiredFieldMissing, CodeInvalidFormat, CodeValueOutOfRange, CodeDuplicateEvent, CodeDatabaseError,
+		CodeRedisError, CodeThirdPartyAPIError, CodeConfigError, CodeFileSystemError, CodeNetworkError,
+	}
+	upperSnake := regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+	seen := make(map[string]ErrorCode, len(all))
+	for _, code := range all {
+		reason := code.GetReason()
+		if reason == "UNKNOWN" || !upperSnake.MatchString(reason) {
+			t.Errorf("GetReason(%d) = %q, want UPPER_SNAKE_CASE name", code, reason)
+		}
+		if other, ok := seen[reason]; ok {
+			t.Errorf("GetReason(%d) = %q, same as %d", code, reason, other)
+		}
+		seen[reason] = code
+	}
+}
+
+func TestGRPCRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    ErrorCode
+		wantMessage string
+		wantDetails interface{}
+	}{
+		{
+			name:        "business code",
+			err:         New(CodeUserNotFound, "user 1 not found"),
+			wantCode:    CodeUserNotFound,
+			wantMessage: "user 1 not found",
+		},
+		{
+			name:        "field violations",
+			err:         New(CodeValidationFailed, "invalid", map[string]string{"email": "invalid", "age": "too small"}),
+			wantCode:    CodeValidationFailed,
+			wantMessage: "invalid",
+			wantDetails: map[string]string{"email": "invalid", "age": "too small"},
+		},
+		{
+			name:        "json details",
+			err:         New(CodeConflict, "conflict", map[string]interface{}{"id": "a"}),
+			wantCode:    CodeConflict,
+			wantMessage: "conflict",
+			wantDetails: map[string]interface{}{"id": "a"},
+		},
+		{
+			name:        "plain status falls back to grpc code",
+			err:         status.Error(codes.PermissionDenied, "denied"),
+			wantCode:    CodeForbidden,
+			wantMessage: "denied",
+		},
+		{
+			name:        "plain error is internal",
+			err:         errors.New("boom"),
+			wantCode:    CodeInternalServerError,
+			wantMessage: "boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var appErr *AppError
+			if !errors.As(FromGRPC(ToGRPC(tt.err)), &appErr) {
+				t.Fatal("FromGRPC() did not return *AppError")
+			}
+			if appErr.Code != tt.wantCode || appErr.Message != tt.wantMessage {
+				t.Errorf("FromGRPC() = %d %q, want %d %q", appErr.Code, appErr.Message, tt.wantCode, tt.wantMessage)
+			}
+			if !reflect.DeepEqual(appErr.Details, tt.wantDetails) {
+				t.Errorf("FromGRPC() details = %#v, want %#v", appErr.Details, tt.wantDetails)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+)
+
+// Key 单个签名/验签密钥
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	signKey interface{} // 私钥或 HMAC 密钥，仅签名密钥需要
+	Public  crypto.PublicKey
+}
+
+// KeySet 当前签名密钥和全部可用的验签密钥
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	methods []string
+}
+
+var (
+	defaultKeySet *KeySet
+	mu            sync.RWMutex
+)
+
+// Default 获取基于全局配置加载的密钥集（首次调用时加载）
+func Default() (*KeySet, error) {
+	mu.RLock()
+	ks := defaultKeySet
+	mu.RUnlock()
+	if ks != nil {
+		return ks, nil
+	}
+	return Reload()
+}
+
+// Reload 重新从配置和密钥文件加载密钥集，用于密钥轮换
+func Reload() (*KeySet, error) {
+	cfg := configx.GetConfig()
+	if cfg == nil {
+		return nil, errors.New("config not loaded")
+	}
+
+	return reloadFrom(cfg.JWT)
+}
+
+// reloadFrom 构建新密钥集并替换默认密钥集，失败时保留当前密钥集
+func reloadFrom(cfg configx.JWTConfig) (*KeySet, error) {
+	ks, err := NewKeySet(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defaultKeySet = ks
+	mu.Unlock()
+	return ks, nil
+}
+
+// NewKeySet 根据 JWT 配置构建密钥集
+func NewKeySet(cfg configx.JWTConfig) (*KeySet, error) {
+	switch cfg.Algorithm {
+	case "", "HS256":
+		if cfg.Secret == "" {
+			return nil, errors.New("jwt secret cannot be empty")
+		}
+		key := &Key{
+			ID:      cfg.SigningKeyID,
+			Method:  jwt.SigningMethodHS256,
+			signKey: []byte(cfg.Secret),
+		}
+		return &KeySet{
+			signing: key,
+			keys:    map[string]*Key{key.ID: key},
+			methods: []string{jwt.SigningMethodHS256.Alg()},
+		}, nil
+	case "RS256", "EdDSA":
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm: %s", cfg.Algorithm)
+	}
+
+	ks := &KeySet{keys: make(map[string]*Key)}
+	seenMethods := make(map[string]bool)
+	for _, keyCfg := range cfg.Keys {
+		alg := keyCfg.Algorithm
+		if alg == "" {
+			alg = cfg.Algorithm
+		}
+
+		key, err := loadKey(keyCfg, alg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt key %s: %w", keyCfg.ID, err)
+		}
+		ks.keys[key.ID] = key
+
+		if !seenMethods[alg] {
+			seenMethods[alg] = true
+			ks.methods = append(ks.methods, alg)
+		}
+	}
+
+	signing, ok := ks.keys[cfg.SigningKeyID]
+	if !ok || signing.signKey == nil {
+		return nil, fmt.Errorf("jwt signing key %s not found or has no private key", cfg.SigningKeyID)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+// Sign 使用当前签名密钥签发令牌，并在头部写入 kid
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	if ks.signing.ID != "" {
+		token.Header["kid"] = ks.signing.ID
+	}
+	return token.SignedString(ks.signing.signKey)
+}
+
+// Keyfunc 根据令牌头部的 kid 选择验签密钥
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	var key *Key
+	if ks.signing.Method == jwt.SigningMethodHS256 {
+		key = ks.signing
+	} else {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token is missing kid header")
+		}
+		var ok bool
+		if key, ok = ks.keys[kid]; !ok {
+			return nil, fmt.Errorf("unknown kid: %s", kid)
+		}
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+
+	if key.Method == jwt.SigningMethodHS256 {
+		return key.signKey, nil
+	}
+	return key.Public, nil
+}
+
+// ParserOptions 限定可接受的签名算法，防止算法混淆攻击
+func (ks *KeySet) ParserOptions() []jwt.ParserOption {
+	return []jwt.ParserOption{jwt.WithValidMethods(ks.methods)}
+}
+
+// loadKey 从 PEM 文件加载密钥，配置私钥时公钥由私钥推导
+func loadKey(cfg configx.JWTKeyConfig, alg string) (*Key, error) {
+	key := &Key{ID: cfg.ID}
+
+	switch alg {
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+	case "EdDSA":
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+
+	if cfg.PrivateKeyFile != "" {
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = signer
+		key.Public = signer.Public()
+	} else {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.Public, err = parsePublicKey(data); err != nil {
+			return nil, err
+		}
+	}
+
+	switch key.Public.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return nil, fmt.Errorf("rsa key cannot be used with %s", alg)
+		}
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return nil, fmt.Errorf("ed25519 key cannot be used with %s", alg)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key.Public)
+	}
+
+	return key, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+
+	// 兼容 openssl 传统格式的 RSA 私钥
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,109 @@
+package jwtx
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"go.uber.org/zap"
+)
+
+// reloadDebounce 合并密钥轮换时短时间内的多次文件事件
+const reloadDebounce = 500 * time.Millisecond
+
+// Watch 监听配置文件和密钥文件所在目录，变化后重新加载密钥集，直到 ctx 取消
+// 轮换时在配置中新增密钥并切换 signing_key_id，旧 kid 签发的令牌在保留期间仍可验签
+func Watch(ctx context.Context, configPath string, logger *zap.Logger) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dirs := make(map[string]bool)
+	watch := func(file string) error {
+		if file == "" {
+			return nil
+		}
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			return nil
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = true
+		return nil
+	}
+	watchKeys := func(cfg configx.JWTConfig) error {
+		for _, key := range cfg.Keys {
+			if err := watch(key.PrivateKeyFile); err != nil {
+				return err
+			}
+			if err := watch(key.PublicKeyFile); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := watch(configPath); err != nil {
+		return err
+	}
+	if cfg := configx.GetConfig(); cfg != nil {
+		if err := watchKeys(cfg.JWT); err != nil {
+			return err
+		}
+	}
+
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(reloadDebounce)
+			} else {
+				timer.Reset(reloadDebounce)
+			}
+			timerC = timer.C
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warn("jwt key watcher error", zap.Error(err))
+		case <-timerC:
+			timerC = nil
+			cfg, err := configx.ReadFile(configPath)
+			if err == nil {
+				_, err = reloadFrom(cfg.JWT)
+			}
+			if err != nil {
+				logger.Error("failed to reload jwt keys, keeping previous ones",
+					zap.String("config_file", configPath),
+					zap.Error(err))
+				continue
+			}
+			// 新增的密钥文件目录也需要监听
+			if err := watchKeys(cfg.JWT); err != nil {
+				logger.Warn("failed to watch jwt key directory", zap.Error(err))
+			}
+			logger.Info("jwt keys reloaded", zap.String("signing_key_id", cfg.JWT.SigningKeyID))
+		}
+	}
+}
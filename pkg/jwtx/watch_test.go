@@ -0,0 +1,190 @@
+package jwtx
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iswangwenbin/gin-starter/pkg/configx"
+	"go.uber.org/zap"
+)
+
+func writeEd25519Key(t *testing.T, dir, kid string) (privateFile, publicFile string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	privateFile = filepath.Join(dir, "jwt-"+kid+".pem")
+	publicFile = filepath.Join(dir, "jwt-"+kid+".pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		t.Fatalf("write private key: %v", err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600); err != nil {
+		t.Fatalf("write public key: %v", err)
+	}
+	return privateFile, publicFile
+}
+
+func writeJWTConfig(t *testing.T, path, signingKeyID, keys string) {
+	t.Helper()
+	content := fmt.Sprintf(`
+database:
+  password: "secret"
+app_auth:
+  allow_plaintext_secrets: true
+jwt:
+  algorithm: EdDSA
+  signing_key_id: %q
+  keys:
+%s`, signingKeyID, keys)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func verify(tokenString string) error {
+	ks, err := Default()
+	if err != nil {
+		return err
+	}
+	_, err = jwt.Parse(tokenString, ks.Keyfunc, ks.ParserOptions()...)
+	return err
+}
+
+func TestWatchRotatesKeysWithoutBreakingOldTokens(t *testing.T) {
+	dir := t.TempDir()
+	oldPrivate, oldPublic := writeEd25519Key(t, dir, "old")
+	newPrivate, _ := writeEd25519Key(t, dir, "new")
+	configPath := filepath.Join(dir, "config.yaml")
+
+	writeJWTConfig(t, configPath, "old", fmt.Sprintf(`    - kid: old
+      private_key_file: %q
+`, oldPrivate))
+	cfg, err := configx.Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	ks, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		defaultKeySet = nil
+		mu.Unlock()
+		configx.GlobalConfig = nil
+	})
+
+	oldToken, err := ks.Sign(jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.Expires))})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watchDone := make(chan error, 1)
+	go func() { watchDone <- Watch(ctx, configPath, zap.NewNop()) }()
+
+	// 轮换期间持续验证旧 kid 签发的令牌
+	var failures atomic.Int64
+	var firstErr atomic.Value
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if err := verify(oldToken); err != nil {
+					failures.Add(1)
+					firstErr.CompareAndSwap(nil, err)
+				}
+			}
+		}()
+	}
+
+	// 等待监听生效后切换签名密钥，旧密钥只保留公钥
+	time.Sleep(100 * time.Millisecond)
+	writeJWTConfig(t, configPath, "new", fmt.Sprintf(`    - kid: new
+      private_key_file: %q
+    - kid: old
+      public_key_file: %q
+`, newPrivate, oldPublic))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		current, err := Default()
+		if err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if current.signing.ID == "new" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("keys were not reloaded after config change")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	close(stop)
+	wg.Wait()
+	if n := failures.Load(); n > 0 {
+		t.Fatalf("old token failed verification %d times during rotation: %v", n, firstErr.Load())
+	}
+
+	current, _ := Default()
+	newToken, err := current.Sign(jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("Sign() after rotation error = %v", err)
+	}
+	parsed, err := jwt.Parse(newToken, current.Keyfunc, current.ParserOptions()...)
+	if err != nil {
+		t.Fatalf("new token verification error = %v", err)
+	}
+	if kid := parsed.Header["kid"]; kid != "new" {
+		t.Errorf("new token kid = %v, want new", kid)
+	}
+	if err := verify(oldToken); err != nil {
+		t.Errorf("old token verification after rotation error = %v", err)
+	}
+
+	// 配置错误时保留当前密钥集
+	if err := os.WriteFile(configPath, []byte("jwt: [invalid"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	time.Sleep(2 * reloadDebounce)
+	if current, _ := Default(); current.signing.ID != "new" {
+		t.Errorf("signing key after invalid config = %s, want new", current.signing.ID)
+	}
+
+	cancel()
+	if err := <-watchDone; err != nil {
+		t.Errorf("Watch() error = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package signx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// 签名相关的 HTTP 请求头，gRPC 使用同名的小写 metadata
+const (
+	HeaderAppID     = "X-App-Id"
+	HeaderKeyID     = "X-Api-Key"
+	HeaderTimestamp = "X-Timestamp" // Unix 秒
+	HeaderSignature = "X-Signature" // 十六进制 HMAC-SHA256
+)
+
+// 仅 gRPC 使用的 metadata
+const (
+	HeaderNonce         = "X-Nonce"          // 每个请求唯一的随机串
+	HeaderContentSHA256 = "X-Content-Sha256" // 客户端实际发送的 protobuf 字节（未压缩）的十六进制 SHA256
+)
+
+// StringToSign 构造待签名字符串：app_id、key_id、时间戳和载荷 SHA256 摘要按行拼接
+func StringToSign(appID, keyID string, timestamp int64, payload []byte) string {
+	return strings.Join([]string{
+		appID,
+		keyID,
+		strconv.FormatInt(timestamp, 10),
+		PayloadSHA256(payload),
+	}, "\n")
+}
+
+// GRPCStringToSign 构造 gRPC 待签名字符串：方法全名、app_id、key_id、时间戳、nonce 和载荷摘要按行拼接；
+// 载荷摘要由客户端对实际发送的字节计算并放在 metadata 中，服务端与收到的字节比对，不依赖重新编码
+func GRPCStringToSign(method, appID, keyID string, timestamp int64, nonce, payloadSHA256 string) string {
+	return strings.Join([]string{
+		method,
+		appID,
+		keyID,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		strings.ToLower(payloadSHA256),
+	}, "\n")
+}
+
+// PayloadSHA256 载荷的十六进制 SHA256 摘要
+func PayloadSHA256(payload []byte) string {
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:])
+}
+
+// Sign 使用密钥计算请求签名
+func Sign(secret, appID, keyID string, timestamp int64, payload []byte) string {
+	return SignString(secret, StringToSign(appID, keyID, timestamp, payload))
+}
+
+// SignString 使用密钥计算待签名字符串的签名
+func SignString(secret, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验请求签名（常量时间比较）
+func Verify(secret, appID, keyID string, timestamp int64, payload []byte, signature string) bool {
+	return VerifyString(secret, StringToSign(appID, keyID, timestamp, payload), signature)
+}
+
+// VerifyString 校验待签名字符串的签名（常量时间比较）
+func VerifyString(secret, stringToSign, signature string) bool {
+	expected := SignString(secret, stringToSign)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
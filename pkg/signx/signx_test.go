@@ -0,0 +1,73 @@
+package signx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringToSign(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "http",
+			got:  StringToSign("app_1", "ak_1", 1700000000, []byte("")),
+			want: "app_1\nak_1\n1700000000\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name: "grpc",
+			got:  GRPCStringToSign("/pkg.Service/Method", "app_1", "ak_1", 1700000000, "n-1", "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"),
+			want: "/pkg.Service/Method\napp_1\nak_1\n1700000000\nn-1\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("string to sign = %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyGRPCSignature(t *testing.T) {
+	const secret = "s3cret"
+	digest := PayloadSHA256([]byte("payload"))
+	canonical := GRPCStringToSign("/pkg.Service/Report", "app_1", "ak_1", 1700000000, "nonce-1", digest)
+	signature := SignString(secret, canonical)
+
+	tests := []struct {
+		name      string
+		secret    string
+		canonical string
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: secret, canonical: canonical, signature: signature, want: true},
+		{name: "uppercase signature", secret: secret, canonical: canonical, signature: strings.ToUpper(signature), want: true},
+		{name: "wrong secret", secret: "other", canonical: canonical, signature: signature},
+		{name: "other method", secret: secret, canonical: GRPCStringToSign("/pkg.Service/Other", "app_1", "ak_1", 1700000000, "nonce-1", digest), signature: signature},
+		{name: "other nonce", secret: secret, canonical: GRPCStringToSign("/pkg.Service/Report", "app_1", "ak_1", 1700000000, "nonce-2", digest), signature: signature},
+		{name: "other timestamp", secret: secret, canonical: GRPCStringToSign("/pkg.Service/Report", "app_1", "ak_1", 1700000001, "nonce-1", digest), signature: signature},
+		{name: "other payload", secret: secret, canonical: GRPCStringToSign("/pkg.Service/Report", "app_1", "ak_1", 1700000000, "nonce-1", PayloadSHA256([]byte("other"))), signature: signature},
+		{name: "empty signature", secret: secret, canonical: canonical, signature: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyString(tt.secret, tt.canonical, tt.signature); got != tt.want {
+				t.Errorf("VerifyString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignMatchesSignString(t *testing.T) {
+	payload := []byte(`{"app_id":"app_1"}`)
+	if got, want := Sign("s", "app_1", "ak_1", 1, payload), SignString("s", StringToSign("app_1", "ak_1", 1, payload)); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if !Verify("s", "app_1", "ak_1", 1, payload, Sign("s", "app_1", "ak_1", 1, payload)) {
+		t.Error("Verify() = false for a valid signature")
+	}
+}